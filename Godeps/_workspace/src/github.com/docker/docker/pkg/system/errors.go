@@ -5,5 +5,6 @@ import (
 )
 
 var (
+	// ErrNotSupportedPlatform means the platform is not supported.
 	ErrNotSupportedPlatform = errors.New("platform and architecture is not supported")
 )
@@ -1,9 +1,13 @@
+// +build linux freebsd
+
 package system
 
 import (
 	"os"
 	"syscall"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 // TestFromStatT tests fromStatT for a tempfile
@@ -13,19 +17,18 @@ func TestFromStatT(t *testing.T) {
 
 	stat := &syscall.Stat_t{}
 	err := syscall.Lstat(file, stat)
+	require.NoError(t, err)
 
 	s, err := fromStatT(stat)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.NoError(t, err)
 
 	if stat.Mode != s.Mode() {
 		t.Fatal("got invalid mode")
 	}
-	if stat.Uid != s.Uid() {
+	if stat.Uid != s.UID() {
 		t.Fatal("got invalid uid")
 	}
-	if stat.Gid != s.Gid() {
+	if stat.Gid != s.GID() {
 		t.Fatal("got invalid gid")
 	}
 	if stat.Rdev != s.Rdev() {
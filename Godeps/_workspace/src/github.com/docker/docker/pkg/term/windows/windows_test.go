@@ -0,0 +1,3 @@
+// This file is necessary to pass the Docker tests.
+
+package windowsconsole
@@ -1,7 +0,0 @@
-// +build !linux,!windows
-
-package system
-
-func ReadMemInfo() (*MemInfo, error) {
-	return nil, ErrNotSupportedPlatform
-}
@@ -1,4 +1,4 @@
-// Copyright 2015 go-dockerclient authors. All rights reserved.
+// Copyright 2013 go-dockerclient authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
@@ -19,7 +19,7 @@ import (
 	"time"
 )
 
-func newTestClient(rt *FakeRoundTripper) Client {
+func newTestClient(rt http.RoundTripper) Client {
 	endpoint := "http://localhost:4243"
 	u, _ := parseEndpoint("http://localhost:4243", false)
 	testAPIVersion, _ := NewAPIVersion("1.17")
@@ -51,6 +51,7 @@ func (m stdinMock) Close() error {
 }
 
 func TestListImages(t *testing.T) {
+	t.Parallel()
 	body := `[
      {
              "Repository":"base",
@@ -103,6 +104,7 @@ func TestListImages(t *testing.T) {
 }
 
 func TestListImagesParameters(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "null", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	_, err := client.ListImages(ListImagesOptions{All: false})
@@ -145,6 +147,7 @@ func TestListImagesParameters(t *testing.T) {
 }
 
 func TestImageHistory(t *testing.T) {
+	t.Parallel()
 	body := `[
 	{
 		"Id": "25daec02219d2d852f7526137213a9b199926b4b24e732eab5b8bc6c49bd470e",
@@ -187,6 +190,7 @@ func TestImageHistory(t *testing.T) {
 }
 
 func TestRemoveImage(t *testing.T) {
+	t.Parallel()
 	name := "test"
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusNoContent}
 	client := newTestClient(fakeRT)
@@ -206,6 +210,7 @@ func TestRemoveImage(t *testing.T) {
 }
 
 func TestRemoveImageNotFound(t *testing.T) {
+	t.Parallel()
 	client := newTestClient(&FakeRoundTripper{message: "no such image", status: http.StatusNotFound})
 	err := client.RemoveImage("test:")
 	if err != ErrNoSuchImage {
@@ -214,6 +219,7 @@ func TestRemoveImageNotFound(t *testing.T) {
 }
 
 func TestRemoveImageExtended(t *testing.T) {
+	t.Parallel()
 	name := "test"
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusNoContent}
 	client := newTestClient(fakeRT)
@@ -237,13 +243,21 @@ func TestRemoveImageExtended(t *testing.T) {
 }
 
 func TestInspectImage(t *testing.T) {
+	t.Parallel()
 	body := `{
      "Id":"b750fe79269d2ec9a3c593ef05b4332b1d1a02a62b4accb2c21d589ff2f5f2dc",
      "Parent":"27cf784147099545",
      "Created":"2013-03-23T22:24:18.818426Z",
      "Container":"3d67245a8d72ecf13f33dffac9f79dcdf70f75acb84d308770391510e0c23ad0",
      "ContainerConfig":{"Memory":1},
-     "VirtualSize":12345
+     "VirtualSize":12345,
+     "RootFS": {
+       "Type": "layers",
+       "Layers": [
+         "sha256:05a0deb2e405eb3095ab646dc1695a26bffe8bd4071e3af90efcf16e9d3f6d93",
+         "sha256:4c5db681b9aa9ab1cf666ec969a810c8ff4410e70e06394670dc4f3bf595532f"
+       ]
+    }
 }`
 
 	created, err := time.Parse(time.RFC3339Nano, "2013-03-23T22:24:18.818426Z")
@@ -260,6 +274,13 @@ func TestInspectImage(t *testing.T) {
 			Memory: 1,
 		},
 		VirtualSize: 12345,
+		RootFS: &RootFS{
+			Type: "layers",
+			Layers: []string{
+				"sha256:05a0deb2e405eb3095ab646dc1695a26bffe8bd4071e3af90efcf16e9d3f6d93",
+				"sha256:4c5db681b9aa9ab1cf666ec969a810c8ff4410e70e06394670dc4f3bf595532f",
+			},
+		},
 	}
 	fakeRT := &FakeRoundTripper{message: body, status: http.StatusOK}
 	client := newTestClient(fakeRT)
@@ -281,6 +302,7 @@ func TestInspectImage(t *testing.T) {
 }
 
 func TestInspectImageNotFound(t *testing.T) {
+	t.Parallel()
 	client := newTestClient(&FakeRoundTripper{message: "no such image", status: http.StatusNotFound})
 	name := "test"
 	image, err := client.InspectImage(name)
@@ -293,6 +315,7 @@ func TestInspectImageNotFound(t *testing.T) {
 }
 
 func TestPushImage(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "Pushing 1/100", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -327,6 +350,7 @@ func TestPushImage(t *testing.T) {
 }
 
 func TestPushImageWithRawJSON(t *testing.T) {
+	t.Parallel()
 	body := `
 	{"status":"Pushing..."}
 	{"status":"Pushing", "progress":"1/? (n/a)", "progressDetail":{"current":1}}}
@@ -356,6 +380,7 @@ func TestPushImageWithRawJSON(t *testing.T) {
 }
 
 func TestPushImageWithAuthentication(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "Pushing 1/100", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -386,6 +411,7 @@ func TestPushImageWithAuthentication(t *testing.T) {
 }
 
 func TestPushImageCustomRegistry(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "Pushing 1/100", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var authConfig AuthConfiguration
@@ -406,6 +432,7 @@ func TestPushImageCustomRegistry(t *testing.T) {
 }
 
 func TestPushImageNoName(t *testing.T) {
+	t.Parallel()
 	client := Client{}
 	err := client.PushImage(PushImageOptions{}, AuthConfiguration{})
 	if err != ErrNoSuchImage {
@@ -414,6 +441,7 @@ func TestPushImageNoName(t *testing.T) {
 }
 
 func TestPullImage(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "Pulling 1/100", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -440,7 +468,78 @@ func TestPullImage(t *testing.T) {
 	}
 }
 
+func TestPullImageWithDigest(t *testing.T) {
+	t.Parallel()
+	fakeRT := &FakeRoundTripper{message: "Pulling 1/100", status: http.StatusOK}
+	client := newTestClient(fakeRT)
+	var buf bytes.Buffer
+	err := client.PullImage(PullImageOptions{
+		Repository:   "tsuru/bs:latest@sha256:504a2f04aa5d07768e4f7467ddd2618b07dd6013cfabca7dc527a3d9fa786580",
+		OutputStream: &buf,
+	}, AuthConfiguration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Pulling 1/100"
+	if buf.String() != expected {
+		t.Errorf("PullImage: Wrong output. Want %q. Got %q.", expected, buf.String())
+	}
+	req := fakeRT.requests[0]
+	if req.Method != "POST" {
+		t.Errorf("PullImage: Wrong HTTP method. Want POST. Got %s.", req.Method)
+	}
+	u, _ := url.Parse(client.getURL("/images/create"))
+	if req.URL.Path != u.Path {
+		t.Errorf("PullImage: Wrong request path. Want %q. Got %q.", u.Path, req.URL.Path)
+	}
+	expectedQuery := url.Values{
+		"fromImage": {"tsuru/bs:latest"},
+		"tag":       {"sha256:504a2f04aa5d07768e4f7467ddd2618b07dd6013cfabca7dc527a3d9fa786580"},
+	}
+	if !reflect.DeepEqual(req.URL.Query(), expectedQuery) {
+		t.Errorf("PullImage: Wrong query string\nWant %#v\nGot  %#v", expectedQuery, req.URL.Query())
+	}
+}
+
+func TestPullImageWithDigestAndTag(t *testing.T) {
+	// This is probably a wrong use of the Docker API, but let's let users
+	// send the request to the API. And also changing this behavior would
+	// be a breaking change on go-dockerclient.
+	t.Parallel()
+	fakeRT := &FakeRoundTripper{message: "Pulling 1/100", status: http.StatusOK}
+	client := newTestClient(fakeRT)
+	var buf bytes.Buffer
+	err := client.PullImage(PullImageOptions{
+		Repository:   "tsuru/bs:latest@sha256:504a2f04aa5d07768e4f7467ddd2618b07dd6013cfabca7dc527a3d9fa786580",
+		Tag:          "latest",
+		OutputStream: &buf,
+	}, AuthConfiguration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Pulling 1/100"
+	if buf.String() != expected {
+		t.Errorf("PullImage: Wrong output. Want %q. Got %q.", expected, buf.String())
+	}
+	req := fakeRT.requests[0]
+	if req.Method != "POST" {
+		t.Errorf("PullImage: Wrong HTTP method. Want POST. Got %s.", req.Method)
+	}
+	u, _ := url.Parse(client.getURL("/images/create"))
+	if req.URL.Path != u.Path {
+		t.Errorf("PullImage: Wrong request path. Want %q. Got %q.", u.Path, req.URL.Path)
+	}
+	expectedQuery := url.Values{
+		"fromImage": {"tsuru/bs:latest@sha256:504a2f04aa5d07768e4f7467ddd2618b07dd6013cfabca7dc527a3d9fa786580"},
+		"tag":       {"latest"},
+	}
+	if !reflect.DeepEqual(req.URL.Query(), expectedQuery) {
+		t.Errorf("PullImage: Wrong query string\nWant %#vGot  %#v", expectedQuery, req.URL.Query())
+	}
+}
+
 func TestPullImageWithRawJSON(t *testing.T) {
+	t.Parallel()
 	body := `
 	{"status":"Pulling..."}
 	{"status":"Pulling", "progress":"1 B/ 100 B", "progressDetail":{"current":1, "total":100}}
@@ -468,6 +567,7 @@ func TestPullImageWithRawJSON(t *testing.T) {
 }
 
 func TestPullImageWithoutOutputStream(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "Pulling 1/100", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	opts := PullImageOptions{
@@ -487,6 +587,7 @@ func TestPullImageWithoutOutputStream(t *testing.T) {
 }
 
 func TestPullImageCustomRegistry(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "Pulling 1/100", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -508,6 +609,7 @@ func TestPullImageCustomRegistry(t *testing.T) {
 }
 
 func TestPullImageTag(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "Pulling 1/100", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -530,6 +632,7 @@ func TestPullImageTag(t *testing.T) {
 }
 
 func TestPullImageNoRepository(t *testing.T) {
+	t.Parallel()
 	var opts PullImageOptions
 	client := Client{}
 	err := client.PullImage(opts, AuthConfiguration{})
@@ -539,6 +642,7 @@ func TestPullImageNoRepository(t *testing.T) {
 }
 
 func TestImportImageFromUrl(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -561,6 +665,7 @@ func TestImportImageFromUrl(t *testing.T) {
 }
 
 func TestImportImageFromInput(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	in := bytes.NewBufferString("tar content")
@@ -590,7 +695,8 @@ func TestImportImageFromInput(t *testing.T) {
 	}
 }
 
-func TestImportImageDoesNotPassesInputIfSourceIsNotDash(t *testing.T) {
+func TestImportImageDoesNotPassInputIfSourceIsNotDash(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -619,6 +725,7 @@ func TestImportImageDoesNotPassesInputIfSourceIsNotDash(t *testing.T) {
 }
 
 func TestImportImageShouldPassTarContentToBodyWhenSourceIsFilePath(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -647,6 +754,7 @@ func TestImportImageShouldPassTarContentToBodyWhenSourceIsFilePath(t *testing.T)
 }
 
 func TestImportImageShouldChangeSourceToDashWhenItsAFilePath(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -668,12 +776,14 @@ func TestImportImageShouldChangeSourceToDashWhenItsAFilePath(t *testing.T) {
 }
 
 func TestBuildImageParameters(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
 	opts := BuildImageOptions{
 		Name:                "testImage",
 		NoCache:             true,
+		CacheFrom:           []string{"test1", "test2"},
 		SuppressOutput:      true,
 		Pull:                true,
 		RmTmpContainer:      true,
@@ -681,26 +791,40 @@ func TestBuildImageParameters(t *testing.T) {
 		Memory:              1024,
 		Memswap:             2048,
 		CPUShares:           10,
+		CPUQuota:            7500,
+		CPUPeriod:           100000,
 		CPUSetCPUs:          "0-3",
+		Ulimits:             []ULimit{{Name: "nofile", Soft: 100, Hard: 200}},
+		BuildArgs:           []BuildArg{{Name: "SOME_VAR", Value: "some_value"}},
 		InputStream:         &buf,
 		OutputStream:        &buf,
+		Labels:              map[string]string{"k": "v"},
+		NetworkMode:         "host",
+		CgroupParent:        "cgparent",
 	}
 	err := client.BuildImage(opts)
-	if err != nil && strings.Index(err.Error(), "build image fail") == -1 {
+	if err != nil && !strings.Contains(err.Error(), "build image fail") {
 		t.Fatal(err)
 	}
 	req := fakeRT.requests[0]
 	expected := map[string][]string{
-		"t":          {opts.Name},
-		"nocache":    {"1"},
-		"q":          {"1"},
-		"pull":       {"1"},
-		"rm":         {"1"},
-		"forcerm":    {"1"},
-		"memory":     {"1024"},
-		"memswap":    {"2048"},
-		"cpushares":  {"10"},
-		"cpusetcpus": {"0-3"},
+		"t":            {opts.Name},
+		"nocache":      {"1"},
+		"q":            {"1"},
+		"pull":         {"1"},
+		"rm":           {"1"},
+		"forcerm":      {"1"},
+		"memory":       {"1024"},
+		"memswap":      {"2048"},
+		"cpushares":    {"10"},
+		"cpuquota":     {"7500"},
+		"cpuperiod":    {"100000"},
+		"cpusetcpus":   {"0-3"},
+		"labels":       {`{"k":"v"}`},
+		"ulimits":      {`[{"Name":"nofile","Soft":100,"Hard":200}]`},
+		"buildargs":    {`{"SOME_VAR":"some_value"}`},
+		"networkmode":  {"host"},
+		"cgroupparent": {"cgparent"},
 	}
 	got := map[string][]string(req.URL.Query())
 	if !reflect.DeepEqual(got, expected) {
@@ -709,6 +833,7 @@ func TestBuildImageParameters(t *testing.T) {
 }
 
 func TestBuildImageParametersForRemoteBuild(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -731,6 +856,7 @@ func TestBuildImageParametersForRemoteBuild(t *testing.T) {
 }
 
 func TestBuildImageMissingRepoAndNilInput(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -746,6 +872,7 @@ func TestBuildImageMissingRepoAndNilInput(t *testing.T) {
 }
 
 func TestBuildImageMissingOutputStream(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	opts := BuildImageOptions{Name: "testImage"}
@@ -756,6 +883,7 @@ func TestBuildImageMissingOutputStream(t *testing.T) {
 }
 
 func TestBuildImageWithRawJSON(t *testing.T) {
+	t.Parallel()
 	body := `
 	{"stream":"Step 0 : FROM ubuntu:latest\n"}
 	{"stream":" ---\u003e 4300eb9d3c8d\n"}
@@ -794,6 +922,7 @@ func TestBuildImageWithRawJSON(t *testing.T) {
 }
 
 func TestBuildImageRemoteWithoutName(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	var buf bytes.Buffer
@@ -815,11 +944,12 @@ func TestBuildImageRemoteWithoutName(t *testing.T) {
 }
 
 func TestTagImageParameters(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	opts := TagImageOptions{Repo: "testImage"}
 	err := client.TagImage("base", opts)
-	if err != nil && strings.Index(err.Error(), "tag image fail") == -1 {
+	if err != nil && !strings.Contains(err.Error(), "tag image fail") {
 		t.Fatal(err)
 	}
 	req := fakeRT.requests[0]
@@ -831,6 +961,7 @@ func TestTagImageParameters(t *testing.T) {
 }
 
 func TestTagImageMissingRepo(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	opts := TagImageOptions{Repo: "testImage"}
@@ -842,6 +973,7 @@ func TestTagImageMissingRepo(t *testing.T) {
 }
 
 func TestIsUrl(t *testing.T) {
+	t.Parallel()
 	url := "http://foo.bar/"
 	result := isURL(url)
 	if !result {
@@ -855,6 +987,7 @@ func TestIsUrl(t *testing.T) {
 }
 
 func TestLoadImage(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
 	tar, err := os.Open("testing/data/container.tar")
@@ -878,6 +1011,7 @@ func TestLoadImage(t *testing.T) {
 }
 
 func TestExportImage(t *testing.T) {
+	t.Parallel()
 	var buf bytes.Buffer
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
@@ -897,6 +1031,7 @@ func TestExportImage(t *testing.T) {
 }
 
 func TestExportImages(t *testing.T) {
+	t.Parallel()
 	var buf bytes.Buffer
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
@@ -917,6 +1052,7 @@ func TestExportImages(t *testing.T) {
 }
 
 func TestExportImagesNoNames(t *testing.T) {
+	t.Parallel()
 	var buf bytes.Buffer
 	fakeRT := &FakeRoundTripper{message: "", status: http.StatusOK}
 	client := newTestClient(fakeRT)
@@ -931,6 +1067,7 @@ func TestExportImagesNoNames(t *testing.T) {
 }
 
 func TestSearchImages(t *testing.T) {
+	t.Parallel()
 	body := `[
 	{
 		"description":"A container with Cassandra 2.0.3",
@@ -969,3 +1106,71 @@ func TestSearchImages(t *testing.T) {
 		t.Errorf("SearchImages: Wrong return value. Want %#v. Got %#v.", expected, result)
 	}
 }
+
+func TestSearchImagesEx(t *testing.T) {
+	t.Parallel()
+	body := `[
+	{
+		"description":"A container with Cassandra 2.0.3",
+		"is_official":true,
+		"is_automated":true,
+		"name":"poklet/cassandra",
+		"star_count":17
+	},
+	{
+		"description":"A container with Cassandra 2.0.3",
+		"is_official":true,
+		"is_automated":false,
+		"name":"poklet/cassandra",
+		"star_count":17
+	}
+	,
+	{
+		"description":"A container with Cassandra 2.0.3",
+		"is_official":false,
+		"is_automated":true,
+		"name":"poklet/cassandra",
+		"star_count":17
+	}
+]`
+	var expected []APIImageSearch
+	err := json.Unmarshal([]byte(body), &expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(&FakeRoundTripper{message: body, status: http.StatusOK})
+	auth := AuthConfiguration{}
+	result, err := client.SearchImagesEx("cassandra", auth)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("SearchImages: Wrong return value. Want %#v. Got %#v.", expected, result)
+	}
+}
+
+func TestPruneImages(t *testing.T) {
+	t.Parallel()
+	results := `{
+		"ImagesDeleted": [
+			{"Deleted": "a"},
+			{"Deleted": "b"},
+			{"Deleted": "c"}
+		],
+		"SpaceReclaimed": 123
+	}`
+
+	expected := &PruneImagesResults{}
+	err := json.Unmarshal([]byte(results), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(&FakeRoundTripper{message: results, status: http.StatusOK})
+	got, err := client.PruneImages(PruneImagesOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("PruneImages: Expected %#v. Got %#v.", expected, got)
+	}
+}
@@ -1,14 +1,14 @@
-// Copyright 2014 go-dockerclient authors. All rights reserved.
+// Copyright 2013 go-dockerclient authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
 package docker
 
 import (
+	"net"
 	"net/http"
 	"net/url"
 	"reflect"
-	"sort"
 	"testing"
 )
 
@@ -19,6 +19,7 @@ type DockerVersion struct {
 }
 
 func TestVersion(t *testing.T) {
+	t.Parallel()
 	body := `{
      "Version":"0.2.2",
      "GitCommit":"5a2a5cc+CHANGES",
@@ -56,6 +57,7 @@ func TestVersion(t *testing.T) {
 }
 
 func TestVersionError(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "internal error", status: http.StatusInternalServerError}
 	client := newTestClient(fakeRT)
 	version, err := client.Version()
@@ -68,35 +70,60 @@ func TestVersionError(t *testing.T) {
 }
 
 func TestInfo(t *testing.T) {
+	t.Parallel()
 	body := `{
      "Containers":11,
      "Images":16,
-     "Debug":0,
+     "Debug":false,
      "NFd":11,
      "NGoroutines":21,
-     "MemoryLimit":1,
-     "SwapLimit":0
+     "MemoryLimit":true,
+     "SwapLimit":false,
+     "RegistryConfig":{
+       "InsecureRegistryCIDRs":["127.0.0.0/8"],
+       "IndexConfigs":{
+         "docker.io":{
+           "Name":"docker.io",
+           "Mirrors":null,
+           "Secure":true,
+           "Official":true
+         }
+       },
+       "Mirrors":null
+     }
 }`
 	fakeRT := FakeRoundTripper{message: body, status: http.StatusOK}
 	client := newTestClient(&fakeRT)
-	expected := Env{}
-	expected.SetInt("Containers", 11)
-	expected.SetInt("Images", 16)
-	expected.SetBool("Debug", false)
-	expected.SetInt("NFd", 11)
-	expected.SetInt("NGoroutines", 21)
-	expected.SetBool("MemoryLimit", true)
-	expected.SetBool("SwapLimit", false)
+	expected := &DockerInfo{
+		Containers:  11,
+		Images:      16,
+		Debug:       false,
+		NFd:         11,
+		NGoroutines: 21,
+		MemoryLimit: true,
+		SwapLimit:   false,
+		RegistryConfig: &ServiceConfig{
+			InsecureRegistryCIDRs: []*NetIPNet{
+				{
+					Mask: net.CIDRMask(8, 32),
+					IP:   net.ParseIP("127.0.0.0").To4(),
+				},
+			},
+			IndexConfigs: map[string]*IndexInfo{
+				"docker.io": {
+					Name:     "docker.io",
+					Secure:   true,
+					Official: true,
+				},
+			},
+		},
+	}
 	info, err := client.Info()
 	if err != nil {
 		t.Fatal(err)
 	}
-	infoSlice := []string(*info)
-	expectedSlice := []string(expected)
-	sort.Strings(infoSlice)
-	sort.Strings(expectedSlice)
-	if !reflect.DeepEqual(expectedSlice, infoSlice) {
-		t.Errorf("Info(): Wrong result.\nWant %#v.\nGot %#v.", expected, *info)
+	if !reflect.DeepEqual(expected, info) {
+		t.Errorf("Info(): Wrong result.\nWant %#v.\nGot %#v.", expected, info)
 	}
 	req := fakeRT.requests[0]
 	if req.Method != "GET" {
@@ -109,6 +136,7 @@ func TestInfo(t *testing.T) {
 }
 
 func TestInfoError(t *testing.T) {
+	t.Parallel()
 	fakeRT := &FakeRoundTripper{message: "internal error", status: http.StatusInternalServerError}
 	client := newTestClient(fakeRT)
 	version, err := client.Info()
@@ -121,6 +149,7 @@ func TestInfoError(t *testing.T) {
 }
 
 func TestParseRepositoryTag(t *testing.T) {
+	t.Parallel()
 	var tests = []struct {
 		input        string
 		expectedRepo string
@@ -146,6 +175,16 @@ func TestParseRepositoryTag(t *testing.T) {
 			"tsuru/python",
 			"2.7",
 		},
+		{
+			"busybox@sha256:4a731fb46adc5cefe3ae374a8b6020fc1b6ad667a279647766e9a3cd89f6fa92",
+			"busybox",
+			"",
+		},
+		{
+			"localhost.localdomain:5000/samalba/hipache:v1@sha256:4a731fb46adc5cefe3ae374a8b6020fc1b6ad667a279647766e9a3cd89f6fa92",
+			"localhost.localdomain:5000/samalba/hipache",
+			"v1",
+		},
 	}
 	for _, tt := range tests {
 		repo, tag := ParseRepositoryTag(tt.input)
@@ -1,10 +1,11 @@
-// Copyright 2015 go-dockerclient authors. All rights reserved.
+// Copyright 2013 go-dockerclient authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
 package testing
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
 	"encoding/json"
@@ -21,10 +22,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/fsouza/go-dockerclient"
 )
 
 func TestNewServer(t *testing.T) {
+	t.Parallel()
 	server, err := NewServer("127.0.0.1:0", nil, nil)
 	if err != nil {
 		t.Fatal(err)
@@ -37,24 +40,59 @@ func TestNewServer(t *testing.T) {
 	conn.Close()
 }
 
+func TestNewTLSServer(t *testing.T) {
+	t.Parallel()
+	tlsConfig := TLSConfig{
+		CertPath:    "./data/server.pem",
+		CertKeyPath: "./data/serverkey.pem",
+		RootCAPath:  "./data/ca.pem",
+	}
+	server, err := NewTLSServer("127.0.0.1:0", nil, nil, tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.listener.Close()
+	conn, err := net.Dial("tcp", server.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	client, err := docker.NewTLSClient(server.URL(), "./data/cert.pem", "./data/key.pem", "./data/ca.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Ping()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestServerStop(t *testing.T) {
+	t.Parallel()
+	const retries = 3
 	server, err := NewServer("127.0.0.1:0", nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 	server.Stop()
 	_, err = net.Dial("tcp", server.listener.Addr().String())
+	for i := 0; i < retries && err == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		_, err = net.Dial("tcp", server.listener.Addr().String())
+	}
 	if err == nil {
 		t.Error("Unexpected <nil> error when dialing to stopped server")
 	}
 }
 
 func TestServerStopNoListener(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.Stop()
 }
 
 func TestServerURL(t *testing.T) {
+	t.Parallel()
 	server, err := NewServer("127.0.0.1:0", nil, nil)
 	if err != nil {
 		t.Fatal(err)
@@ -67,6 +105,7 @@ func TestServerURL(t *testing.T) {
 }
 
 func TestServerURLNoListener(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	url := server.URL()
 	if url != "" {
@@ -75,6 +114,7 @@ func TestServerURLNoListener(t *testing.T) {
 }
 
 func TestHandleWithHook(t *testing.T) {
+	t.Parallel()
 	var called bool
 	server, _ := NewServer("127.0.0.1:0", nil, func(*http.Request) { called = true })
 	defer server.Stop()
@@ -87,6 +127,7 @@ func TestHandleWithHook(t *testing.T) {
 }
 
 func TestSetHook(t *testing.T) {
+	t.Parallel()
 	var called bool
 	server, _ := NewServer("127.0.0.1:0", nil, nil)
 	defer server.Stop()
@@ -100,8 +141,10 @@ func TestSetHook(t *testing.T) {
 }
 
 func TestCustomHandler(t *testing.T) {
+	t.Parallel()
 	var called bool
 	server, _ := NewServer("127.0.0.1:0", nil, nil)
+	defer server.Stop()
 	addContainers(server, 2)
 	server.CustomHandler("/containers/json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		called = true
@@ -119,8 +162,10 @@ func TestCustomHandler(t *testing.T) {
 }
 
 func TestCustomHandlerRegexp(t *testing.T) {
+	t.Parallel()
 	var called bool
 	server, _ := NewServer("127.0.0.1:0", nil, nil)
+	defer server.Stop()
 	addContainers(server, 2)
 	server.CustomHandler("/containers/.*/json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		called = true
@@ -138,6 +183,7 @@ func TestCustomHandlerRegexp(t *testing.T) {
 }
 
 func TestListContainers(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 2)
 	server.buildMuxer()
@@ -157,6 +203,7 @@ func TestListContainers(t *testing.T) {
 			Status:  container.State.String(),
 			Ports:   container.NetworkSettings.PortMappingAPI(),
 			Names:   []string{"/" + container.Name},
+			State:   container.State.StateString(),
 		}
 	}
 	var got []docker.APIContainers
@@ -170,6 +217,7 @@ func TestListContainers(t *testing.T) {
 }
 
 func TestListRunningContainers(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 2)
 	server.buildMuxer()
@@ -190,8 +238,10 @@ func TestListRunningContainers(t *testing.T) {
 }
 
 func TestCreateContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.imgIDs = map[string]string{"base": "a1234"}
+	server.uploadedFiles = map[string]string{"a1234": "/abcd"}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
 	body := `{"Hostname":"", "User":"ubuntu", "Memory":0, "MemorySwap":0, "AttachStdin":false, "AttachStdout":true, "AttachStderr":true,
@@ -213,6 +263,9 @@ func TestCreateContainer(t *testing.T) {
 	if stored.State.Running {
 		t.Errorf("CreateContainer should not set container to running state.")
 	}
+	if !stored.State.StartedAt.IsZero() {
+		t.Errorf("CreateContainer should not set startedAt in container state.")
+	}
 	if stored.Config.User != "ubuntu" {
 		t.Errorf("CreateContainer: wrong config. Expected: %q. Returned: %q.", "ubuntu", stored.Config.User)
 	}
@@ -223,9 +276,15 @@ func TestCreateContainer(t *testing.T) {
 	if !reflect.DeepEqual(stored.HostConfig.Binds, expectedBind) {
 		t.Errorf("CreateContainer: wrong host config. Expected: %v. Returned %v.", expectedBind, stored.HostConfig.Binds)
 	}
+	if val, ok := server.uploadedFiles[stored.ID]; !ok {
+		t.Error("CreateContainer: uploadedFiles should exist.")
+	} else if val != "/abcd" {
+		t.Errorf("CreateContainer: wrong uploadedFile. Want '/abcd', got %s.", val)
+	}
 }
 
 func TestCreateContainerWithNotifyChannel(t *testing.T) {
+	t.Parallel()
 	ch := make(chan *docker.Container, 1)
 	server := DockerServer{}
 	server.imgIDs = map[string]string{"base": "a1234"}
@@ -245,6 +304,7 @@ func TestCreateContainerWithNotifyChannel(t *testing.T) {
 }
 
 func TestCreateContainerInvalidBody(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -256,6 +316,7 @@ func TestCreateContainerInvalidBody(t *testing.T) {
 }
 
 func TestCreateContainerDuplicateName(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	server.imgIDs = map[string]string{"base": "a1234"}
@@ -272,6 +333,7 @@ func TestCreateContainerDuplicateName(t *testing.T) {
 }
 
 func TestCreateMultipleContainersEmptyName(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	server.imgIDs = map[string]string{"base": "a1234"}
@@ -307,6 +369,7 @@ func TestCreateMultipleContainersEmptyName(t *testing.T) {
 }
 
 func TestCreateContainerInvalidName(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -325,6 +388,7 @@ func TestCreateContainerInvalidName(t *testing.T) {
 }
 
 func TestCreateContainerImageNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -339,6 +403,7 @@ func TestCreateContainerImageNotFound(t *testing.T) {
 }
 
 func TestRenameContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 2)
 	server.buildMuxer()
@@ -357,6 +422,7 @@ func TestRenameContainer(t *testing.T) {
 }
 
 func TestRenameContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -368,8 +434,10 @@ func TestRenameContainerNotFound(t *testing.T) {
 }
 
 func TestCommitContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 2)
+	server.uploadedFiles = map[string]string{server.containers[0].ID: "/abcd"}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
 	request, _ := http.NewRequest("POST", "/commit?container="+server.containers[0].ID, nil)
@@ -381,9 +449,18 @@ func TestCommitContainer(t *testing.T) {
 	if got := recorder.Body.String(); got != expected {
 		t.Errorf("CommitContainer: wrong response body. Want %q. Got %q.", expected, got)
 	}
+	if server.images[0].Config == nil {
+		t.Error("CommitContainer: image Config should not be nil.")
+	}
+	if val, ok := server.uploadedFiles[server.images[0].ID]; !ok {
+		t.Error("CommitContainer: uploadedFiles should exist.")
+	} else if val != "/abcd" {
+		t.Errorf("CommitContainer: wrong uploadedFile. Want '/abcd', got %s.", val)
+	}
 }
 
 func TestCommitContainerComplete(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.imgIDs = make(map[string]string)
 	addContainers(&server, 2)
@@ -422,6 +499,7 @@ func TestCommitContainerComplete(t *testing.T) {
 }
 
 func TestCommitContainerWithTag(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.imgIDs = make(map[string]string)
 	addContainers(&server, 2)
@@ -443,6 +521,7 @@ func TestCommitContainerWithTag(t *testing.T) {
 }
 
 func TestCommitContainerInvalidRun(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -455,6 +534,7 @@ func TestCommitContainerInvalidRun(t *testing.T) {
 }
 
 func TestCommitContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -466,6 +546,7 @@ func TestCommitContainerNotFound(t *testing.T) {
 }
 
 func TestInspectContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 2)
 	server.buildMuxer()
@@ -499,6 +580,7 @@ func TestInspectContainer(t *testing.T) {
 }
 
 func TestInspectContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -510,6 +592,7 @@ func TestInspectContainerNotFound(t *testing.T) {
 }
 
 func TestTopContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Running = true
@@ -538,6 +621,7 @@ func TestTopContainer(t *testing.T) {
 }
 
 func TestTopContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -549,6 +633,7 @@ func TestTopContainerNotFound(t *testing.T) {
 }
 
 func TestTopContainerStopped(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -562,6 +647,7 @@ func TestTopContainerStopped(t *testing.T) {
 }
 
 func TestStartContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -581,12 +667,70 @@ func TestStartContainer(t *testing.T) {
 	if !server.containers[0].State.Running {
 		t.Error("StartContainer: did not set the container to running state")
 	}
+	if server.containers[0].State.StartedAt.IsZero() {
+		t.Error("StartContainer: did not set the startedAt container state")
+	}
 	if gotMemory := server.containers[0].HostConfig.Memory; gotMemory != memory {
 		t.Errorf("StartContainer: wrong HostConfig. Wants %d of memory. Got %d", memory, gotMemory)
 	}
 }
 
+func TestStartContainerNoHostConfig(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	addContainers(&server, 1)
+	server.buildMuxer()
+	memory := int64(536870912)
+	hostConfig := docker.HostConfig{Memory: memory}
+	server.containers[0].HostConfig = &hostConfig
+	recorder := httptest.NewRecorder()
+	path := fmt.Sprintf("/containers/%s/start", server.containers[0].ID)
+	request, _ := http.NewRequest("POST", path, strings.NewReader(""))
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("StartContainer: wrong status code. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+	if !server.containers[0].State.Running {
+		t.Error("StartContainer: did not set the container to running state")
+	}
+	if gotMemory := server.containers[0].HostConfig.Memory; gotMemory != memory {
+		t.Errorf("StartContainer: wrong HostConfig. Wants %d of memory. Got %d", memory, gotMemory)
+	}
+}
+
+func TestStartContainerChangeNetwork(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	addContainers(&server, 1)
+	server.buildMuxer()
+	hostConfig := docker.HostConfig{
+		PortBindings: map[docker.Port][]docker.PortBinding{
+			"8888/tcp": {{HostIP: "", HostPort: "12345"}},
+		},
+	}
+	configBytes, err := json.Marshal(hostConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	path := fmt.Sprintf("/containers/%s/start", server.containers[0].ID)
+	request, _ := http.NewRequest("POST", path, bytes.NewBuffer(configBytes))
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("StartContainer: wrong status code. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+	if !server.containers[0].State.Running {
+		t.Error("StartContainer: did not set the container to running state")
+	}
+	portMapping := server.containers[0].NetworkSettings.Ports["8888/tcp"]
+	expected := []docker.PortBinding{{HostIP: "0.0.0.0", HostPort: "12345"}}
+	if !reflect.DeepEqual(portMapping, expected) {
+		t.Errorf("StartContainer: network not updated. Wants %#v ports. Got %#v", expected, portMapping)
+	}
+}
+
 func TestStartContainerWithNotifyChannel(t *testing.T) {
+	t.Parallel()
 	ch := make(chan *docker.Container, 1)
 	server := DockerServer{}
 	server.cChan = ch
@@ -606,6 +750,7 @@ func TestStartContainerWithNotifyChannel(t *testing.T) {
 }
 
 func TestStartContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -618,6 +763,7 @@ func TestStartContainerNotFound(t *testing.T) {
 }
 
 func TestStartContainerAlreadyRunning(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Running = true
@@ -632,6 +778,7 @@ func TestStartContainerAlreadyRunning(t *testing.T) {
 }
 
 func TestStopContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Running = true
@@ -649,6 +796,7 @@ func TestStopContainer(t *testing.T) {
 }
 
 func TestKillContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Running = true
@@ -666,6 +814,7 @@ func TestKillContainer(t *testing.T) {
 }
 
 func TestStopContainerWithNotifyChannel(t *testing.T) {
+	t.Parallel()
 	ch := make(chan *docker.Container, 1)
 	server := DockerServer{}
 	server.cChan = ch
@@ -686,6 +835,7 @@ func TestStopContainerWithNotifyChannel(t *testing.T) {
 }
 
 func TestStopContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -698,6 +848,7 @@ func TestStopContainerNotFound(t *testing.T) {
 }
 
 func TestStopContainerNotRunning(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -711,6 +862,7 @@ func TestStopContainerNotRunning(t *testing.T) {
 }
 
 func TestPauseContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -727,6 +879,7 @@ func TestPauseContainer(t *testing.T) {
 }
 
 func TestPauseContainerAlreadyPaused(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Paused = true
@@ -741,6 +894,7 @@ func TestPauseContainerAlreadyPaused(t *testing.T) {
 }
 
 func TestPauseContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -753,6 +907,7 @@ func TestPauseContainerNotFound(t *testing.T) {
 }
 
 func TestUnpauseContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Paused = true
@@ -770,6 +925,7 @@ func TestUnpauseContainer(t *testing.T) {
 }
 
 func TestUnpauseContainerNotPaused(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -783,6 +939,7 @@ func TestUnpauseContainerNotPaused(t *testing.T) {
 }
 
 func TestUnpauseContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -795,6 +952,7 @@ func TestUnpauseContainerNotFound(t *testing.T) {
 }
 
 func TestWaitContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Running = true
@@ -818,6 +976,7 @@ func TestWaitContainer(t *testing.T) {
 }
 
 func TestWaitContainerStatus(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -836,6 +995,7 @@ func TestWaitContainerStatus(t *testing.T) {
 }
 
 func TestWaitContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -866,7 +1026,36 @@ func (r *HijackableResponseRecorder) HijackBuffer() string {
 	return string(<-r.readCh)
 }
 
+func TestLogContainer(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	addContainers(&server, 1)
+	server.containers[0].State.Running = true
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	path := fmt.Sprintf("/containers/%s/logs", server.containers[0].ID)
+	request, _ := http.NewRequest("GET", path, nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("LogContainer: wrong status. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+}
+
+func TestLogContainerNotFound(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	path := "/containers/abc123/logs"
+	request, _ := http.NewRequest("GET", path, nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("LogContainer: wrong status. Want %d. Got %d.", http.StatusNotFound, recorder.Code)
+	}
+}
+
 func TestAttachContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Running = true
@@ -887,6 +1076,7 @@ func TestAttachContainer(t *testing.T) {
 }
 
 func TestAttachContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := &HijackableResponseRecorder{}
@@ -899,6 +1089,7 @@ func TestAttachContainerNotFound(t *testing.T) {
 }
 
 func TestAttachContainerWithStreamBlocks(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Running = true
@@ -936,7 +1127,48 @@ func TestAttachContainerWithStreamBlocks(t *testing.T) {
 	}
 }
 
+func TestAttachContainerWithStreamBlocksOnCreatedContainers(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	addContainers(&server, 1)
+	server.containers[0].State.Running = false
+	server.containers[0].State.StartedAt = time.Time{}
+	server.buildMuxer()
+	path := fmt.Sprintf("/containers/%s/attach?logs=1&stdout=1&stream=1", server.containers[0].ID)
+	request, _ := http.NewRequest("POST", path, nil)
+	done := make(chan string)
+	go func() {
+		recorder := &HijackableResponseRecorder{}
+		server.ServeHTTP(recorder, request)
+		done <- recorder.HijackBuffer()
+	}()
+	select {
+	case <-done:
+		t.Fatalf("attach stream returned before container is stopped")
+	case <-time.After(500 * time.Millisecond):
+	}
+	server.cMut.Lock()
+	server.containers[0].State.StartedAt = time.Now()
+	server.cMut.Unlock()
+	var body string
+	select {
+	case body = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for attach to finish")
+	}
+	lines := []string{
+		"\x01\x00\x00\x00\x00\x00\x00\x19Container is not running",
+		"\x01\x00\x00\x00\x00\x00\x00\x0fWhat happened?",
+		"\x01\x00\x00\x00\x00\x00\x00\x13Something happened",
+	}
+	expected := strings.Join(lines, "\n") + "\n"
+	if body != expected {
+		t.Errorf("AttachContainer: wrong body. Want %q. Got %q.", expected, body)
+	}
+}
+
 func TestRemoveContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -953,6 +1185,7 @@ func TestRemoveContainer(t *testing.T) {
 }
 
 func TestRemoveContainerByName(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -969,6 +1202,7 @@ func TestRemoveContainerByName(t *testing.T) {
 }
 
 func TestRemoveContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -981,6 +1215,7 @@ func TestRemoveContainerNotFound(t *testing.T) {
 }
 
 func TestRemoveContainerRunning(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Running = true
@@ -998,6 +1233,7 @@ func TestRemoveContainerRunning(t *testing.T) {
 }
 
 func TestRemoveContainerRunningForce(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.containers[0].State.Running = true
@@ -1015,6 +1251,7 @@ func TestRemoveContainerRunningForce(t *testing.T) {
 }
 
 func TestPullImage(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{imgIDs: make(map[string]string)}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1029,9 +1266,13 @@ func TestPullImage(t *testing.T) {
 	if _, ok := server.imgIDs["base"]; !ok {
 		t.Error("PullImage: Repository should not be empty.")
 	}
+	if server.images[0].Config == nil {
+		t.Error("PullImage: Image Config should not be nil.")
+	}
 }
 
 func TestPullImageWithTag(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{imgIDs: make(map[string]string)}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1048,7 +1289,26 @@ func TestPullImageWithTag(t *testing.T) {
 	}
 }
 
+func TestPullImageWithShaTag(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{imgIDs: make(map[string]string)}
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/images/create?fromImage=base&tag=sha256:deadc0de", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("PullImage: wrong status. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+	if len(server.images) != 1 {
+		t.Errorf("PullImage: Want 1 image. Got %d.", len(server.images))
+	}
+	if _, ok := server.imgIDs["base@sha256:deadc0de"]; !ok {
+		t.Error("PullImage: Repository should not be empty.")
+	}
+}
+
 func TestPushImage(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{imgIDs: map[string]string{"tsuru/python": "a123"}}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1060,6 +1320,7 @@ func TestPushImage(t *testing.T) {
 }
 
 func TestPushImageWithTag(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{imgIDs: map[string]string{"tsuru/python:v1": "a123"}}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1071,6 +1332,7 @@ func TestPushImageWithTag(t *testing.T) {
 }
 
 func TestPushImageNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1082,6 +1344,7 @@ func TestPushImageNotFound(t *testing.T) {
 }
 
 func TestTagImage(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{imgIDs: map[string]string{"tsuru/python": "a123"}}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1096,6 +1359,7 @@ func TestTagImage(t *testing.T) {
 }
 
 func TestTagImageWithRepoAndTag(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{imgIDs: map[string]string{"tsuru/python": "a123"}}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1110,6 +1374,7 @@ func TestTagImageWithRepoAndTag(t *testing.T) {
 }
 
 func TestTagImageNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1154,6 +1419,11 @@ func addContainers(server *DockerServer, n int) {
 				PortMapping: map[string]docker.PortMapping{
 					"Tcp": {"8888": fmt.Sprintf("%d", 49600+i)},
 				},
+				Ports: map[docker.Port][]docker.PortBinding{
+					"8888/tcp": {
+						{HostIP: "0.0.0.0", HostPort: fmt.Sprintf("%d", 49600+i)},
+					},
+				},
 			},
 			ResolvConfPath: "/etc/resolv.conf",
 		}
@@ -1182,6 +1452,7 @@ func addImages(server *DockerServer, n int, repo bool) {
 }
 
 func TestListImages(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addImages(&server, 2, true)
 	server.buildMuxer()
@@ -1210,6 +1481,7 @@ func TestListImages(t *testing.T) {
 }
 
 func TestRemoveImage(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addImages(&server, 1, false)
 	server.buildMuxer()
@@ -1226,6 +1498,7 @@ func TestRemoveImage(t *testing.T) {
 }
 
 func TestRemoveImageByName(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addImages(&server, 1, true)
 	server.buildMuxer()
@@ -1247,6 +1520,7 @@ func TestRemoveImageByName(t *testing.T) {
 }
 
 func TestRemoveImageWithMultipleTags(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addImages(&server, 1, true)
 	server.buildMuxer()
@@ -1277,6 +1551,7 @@ func TestRemoveImageWithMultipleTags(t *testing.T) {
 }
 
 func TestPrepareFailure(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{failures: make(map[string]string)}
 	server.buildMuxer()
 	errorID := "my_error"
@@ -1293,6 +1568,7 @@ func TestPrepareFailure(t *testing.T) {
 }
 
 func TestPrepareMultiFailures(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{multiFailures: []map[string]string{}}
 	server.buildMuxer()
 	errorID := "multi error"
@@ -1328,6 +1604,7 @@ func TestPrepareMultiFailures(t *testing.T) {
 }
 
 func TestRemoveFailure(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{failures: make(map[string]string)}
 	server.buildMuxer()
 	errorID := "my_error"
@@ -1348,6 +1625,7 @@ func TestRemoveFailure(t *testing.T) {
 }
 
 func TestResetMultiFailures(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{multiFailures: []map[string]string{}}
 	server.buildMuxer()
 	errorID := "multi error"
@@ -1363,6 +1641,7 @@ func TestResetMultiFailures(t *testing.T) {
 }
 
 func TestMutateContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{failures: make(map[string]string)}
 	server.buildMuxer()
 	server.containers = append(server.containers, &docker.Container{ID: "id123"})
@@ -1378,6 +1657,7 @@ func TestMutateContainer(t *testing.T) {
 }
 
 func TestMutateContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{failures: make(map[string]string)}
 	server.buildMuxer()
 	state := docker.State{Running: false, ExitCode: 1}
@@ -1391,6 +1671,7 @@ func TestMutateContainerNotFound(t *testing.T) {
 }
 
 func TestBuildImageWithContentTypeTar(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{imgIDs: make(map[string]string)}
 	imageName := "teste"
 	recorder := httptest.NewRecorder()
@@ -1406,23 +1687,25 @@ func TestBuildImageWithContentTypeTar(t *testing.T) {
 		t.Errorf("BuildImage: miss Dockerfile")
 		return
 	}
-	if _, ok := server.imgIDs[imageName]; ok == false {
+	if _, ok := server.imgIDs[imageName]; !ok {
 		t.Errorf("BuildImage: image %s not builded", imageName)
 	}
 }
 
 func TestBuildImageWithRemoteDockerfile(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{imgIDs: make(map[string]string)}
 	imageName := "teste"
 	recorder := httptest.NewRecorder()
 	request, _ := http.NewRequest("POST", "/build?t=teste&remote=http://localhost/Dockerfile", nil)
 	server.buildImage(recorder, request)
-	if _, ok := server.imgIDs[imageName]; ok == false {
+	if _, ok := server.imgIDs[imageName]; !ok {
 		t.Errorf("BuildImage: image %s not builded", imageName)
 	}
 }
 
 func TestPing(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	recorder := httptest.NewRecorder()
 	request, _ := http.NewRequest("GET", "/_ping", nil)
@@ -1436,6 +1719,7 @@ func TestPing(t *testing.T) {
 }
 
 func TestDefaultHandler(t *testing.T) {
+	t.Parallel()
 	server, err := NewServer("127.0.0.1:0", nil, nil)
 	if err != nil {
 		t.Fatal(err)
@@ -1447,6 +1731,7 @@ func TestDefaultHandler(t *testing.T) {
 }
 
 func TestCreateExecContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 2)
 	server.buildMuxer()
@@ -1467,20 +1752,23 @@ func TestCreateExecContainer(t *testing.T) {
 	if got.ID != serverExec.ID {
 		t.Errorf("CreateExec: wrong value. Want %#v. Got %#v.", serverExec.ID, got.ID)
 	}
+
 	expected := docker.ExecInspect{
 		ID: got.ID,
 		ProcessConfig: docker.ExecProcessConfig{
 			EntryPoint: "bash",
 			Arguments:  []string{"-c", "ls"},
 		},
-		Container: *server.containers[0],
+		ContainerID: server.containers[0].ID,
 	}
+
 	if !reflect.DeepEqual(*serverExec, expected) {
 		t.Errorf("InspectContainer: wrong value. Want:\n%#v\nGot:\n%#v\n", expected, *serverExec)
 	}
 }
 
 func TestInspectExecContainer(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addContainers(&server, 1)
 	server.buildMuxer()
@@ -1514,20 +1802,18 @@ func TestInspectExecContainer(t *testing.T) {
 			EntryPoint: "bash",
 			Arguments:  []string{"-c", "ls"},
 		},
-		Container: *server.containers[0],
+		ContainerID: server.containers[0].ID,
 	}
-	got2.Container.State.StartedAt = expected.Container.State.StartedAt
-	got2.Container.State.FinishedAt = expected.Container.State.FinishedAt
-	got2.Container.Config = expected.Container.Config
-	got2.Container.Created = expected.Container.Created
-	got2.Container.NetworkSettings = expected.Container.NetworkSettings
+
 	if !reflect.DeepEqual(got2, expected) {
 		t.Errorf("InspectContainer: wrong value. Want:\n%#v\nGot:\n%#v\n", expected, got2)
 	}
 }
 
 func TestStartExecContainer(t *testing.T) {
+	t.Parallel()
 	server, _ := NewServer("127.0.0.1:0", nil, nil)
+	defer server.Stop()
 	addContainers(server, 1)
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1580,7 +1866,9 @@ func TestStartExecContainer(t *testing.T) {
 }
 
 func TestStartExecContainerWildcardCallback(t *testing.T) {
+	t.Parallel()
 	server, _ := NewServer("127.0.0.1:0", nil, nil)
+	defer server.Stop()
 	addContainers(server, 1)
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1633,7 +1921,9 @@ func TestStartExecContainerWildcardCallback(t *testing.T) {
 }
 
 func TestStartExecContainerNotFound(t *testing.T) {
+	t.Parallel()
 	server, _ := NewServer("127.0.0.1:0", nil, nil)
+	defer server.Stop()
 	addContainers(server, 1)
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
@@ -1656,6 +1946,7 @@ func waitExec(url, execID string, running bool, maxTry int) (*docker.ExecInspect
 }
 
 func TestStatsContainer(t *testing.T) {
+	t.Parallel()
 	server, err := NewServer("127.0.0.1:0", nil, nil)
 	if err != nil {
 		t.Fatal(err)
@@ -1682,6 +1973,7 @@ func TestStatsContainer(t *testing.T) {
 		t.Fatal(err)
 	}
 	got.Read = time.Time{}
+	got.PreRead = time.Time{}
 	if !reflect.DeepEqual(got, expected) {
 		t.Errorf("StatsContainer: wrong value. Want %#v. Got %#v.", expected, got)
 	}
@@ -1699,6 +1991,7 @@ func (w *safeWriter) Write(buf []byte) (int, error) {
 }
 
 func TestStatsContainerStream(t *testing.T) {
+	t.Parallel()
 	server, err := NewServer("127.0.0.1:0", nil, nil)
 	if err != nil {
 		t.Fatal(err)
@@ -1734,6 +2027,7 @@ func TestStatsContainerStream(t *testing.T) {
 		t.Fatal(err)
 	}
 	got.Read = time.Time{}
+	got.PreRead = time.Time{}
 	if !reflect.DeepEqual(got, expected) {
 		t.Errorf("StatsContainer: wrong value. Want %#v. Got %#v.", expected, got)
 	}
@@ -1745,14 +2039,13 @@ func addNetworks(server *DockerServer, n int) {
 	for i := 0; i < n; i++ {
 		netid := fmt.Sprintf("%x", rand.Int()%10000)
 		network := docker.Network{
-			Name: netid,
-			ID:   fmt.Sprintf("%x", rand.Int()%10000),
-			Type: "bridge",
-			Endpoints: []*docker.Endpoint{
-				{
-					Name:    "blah",
-					ID:      fmt.Sprintf("%x", rand.Int()%10000),
-					Network: netid,
+			Name:   netid,
+			ID:     fmt.Sprintf("%x", rand.Int()%10000),
+			Driver: "bridge",
+			Containers: map[string]docker.Endpoint{
+				"blah": {
+					Name: "blah",
+					ID:   fmt.Sprintf("%x", rand.Int()%10000),
 				},
 			},
 		}
@@ -1761,6 +2054,7 @@ func addNetworks(server *DockerServer, n int) {
 }
 
 func TestListNetworks(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	addNetworks(&server, 2)
 	server.buildMuxer()
@@ -1773,10 +2067,10 @@ func TestListNetworks(t *testing.T) {
 	expected := make([]docker.Network, 2)
 	for i, network := range server.networks {
 		expected[i] = docker.Network{
-			ID:        network.ID,
-			Name:      network.Name,
-			Type:      network.Type,
-			Endpoints: network.Endpoints,
+			ID:         network.ID,
+			Name:       network.Name,
+			Driver:     network.Driver,
+			Containers: network.Containers,
 		}
 	}
 	var got []docker.Network
@@ -1794,13 +2088,14 @@ type createNetworkResponse struct {
 }
 
 func TestCreateNetwork(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
 	netid := fmt.Sprintf("%x", rand.Int()%10000)
 	netname := fmt.Sprintf("%x", rand.Int()%10000)
 	body := fmt.Sprintf(`{"ID": "%s", "Name": "%s", "Type": "bridge" }`, netid, netname)
-	request, _ := http.NewRequest("POST", "/networks", strings.NewReader(body))
+	request, _ := http.NewRequest("POST", "/networks/create", strings.NewReader(body))
 	server.ServeHTTP(recorder, request)
 	if recorder.Code != http.StatusCreated {
 		t.Errorf("CreateNetwork: wrong status. Want %d. Got %d.", http.StatusCreated, recorder.Code)
@@ -1818,10 +2113,11 @@ func TestCreateNetwork(t *testing.T) {
 }
 
 func TestCreateNetworkInvalidBody(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	recorder := httptest.NewRecorder()
-	request, _ := http.NewRequest("POST", "/networks", strings.NewReader("whaaaaaat---"))
+	request, _ := http.NewRequest("POST", "/networks/create", strings.NewReader("whaaaaaat---"))
 	server.ServeHTTP(recorder, request)
 	if recorder.Code != http.StatusBadRequest {
 		t.Errorf("CreateNetwork: wrong status. Want %d. Got %d.", http.StatusBadRequest, recorder.Code)
@@ -1829,15 +2125,442 @@ func TestCreateNetworkInvalidBody(t *testing.T) {
 }
 
 func TestCreateNetworkDuplicateName(t *testing.T) {
+	t.Parallel()
 	server := DockerServer{}
 	server.buildMuxer()
 	addNetworks(&server, 1)
 	server.networks[0].Name = "mynetwork"
 	recorder := httptest.NewRecorder()
 	body := fmt.Sprintf(`{"ID": "%s", "Name": "mynetwork", "Type": "bridge" }`, fmt.Sprintf("%x", rand.Int()%10000))
-	request, _ := http.NewRequest("POST", "/networks", strings.NewReader(body))
+	request, _ := http.NewRequest("POST", "/networks/create", strings.NewReader(body))
 	server.ServeHTTP(recorder, request)
 	if recorder.Code != http.StatusForbidden {
 		t.Errorf("CreateNetwork: wrong status. Want %d. Got %d.", http.StatusForbidden, recorder.Code)
 	}
 }
+
+func TestRemoveNetwork(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	server.networks = []*docker.Network{
+		{ID: "id1", Name: "name1"},
+		{ID: "id2", Name: "name2"},
+	}
+	request, _ := http.NewRequest("DELETE", "/networks/id1", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("RemoveNetwork: wrong status. Want %d. Got %d.", http.StatusNoContent, recorder.Code)
+	}
+	expected := []*docker.Network{{ID: "id2", Name: "name2"}}
+	if !reflect.DeepEqual(server.networks, expected) {
+		t.Errorf("RemoveNetwork: expected networks to be %#v, got %#v", expected, server.networks)
+	}
+	request, _ = http.NewRequest("DELETE", "/networks/name2", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("RemoveNetwork: wrong status. Want %d. Got %d.", http.StatusNoContent, recorder.Code)
+	}
+	expected = []*docker.Network{}
+	if !reflect.DeepEqual(server.networks, expected) {
+		t.Errorf("RemoveNetwork: expected networks to be %#v, got %#v", expected, server.networks)
+	}
+}
+
+func TestListVolumes(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	expected := []docker.Volume{{
+		Name:       "test-vol-1",
+		Driver:     "local",
+		Mountpoint: "/var/lib/docker/volumes/test-vol-1",
+	}}
+	server.volStore = make(map[string]*volumeCounter)
+	for _, vol := range expected {
+		server.volStore[vol.Name] = &volumeCounter{
+			volume: vol,
+			count:  0,
+		}
+	}
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/volumes", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("ListVolumes: wrong status.  Want %d. Got %d.", http.StatusCreated, recorder.Code)
+	}
+	var got map[string][]docker.Volume
+	err := json.NewDecoder(recorder.Body).Decode(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotVolumes, ok := got["Volumes"]
+	if !ok {
+		t.Fatal(fmt.Errorf("ListVolumes failed can not find Volumes"))
+	}
+	if !reflect.DeepEqual(gotVolumes, expected) {
+		t.Errorf("ListVolumes.  Want %#v.  Got %#v.", expected, got)
+	}
+}
+
+func TestCreateVolume(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	body := `{"Name":"test-volume"}`
+	request, _ := http.NewRequest("POST", "/volumes/create", strings.NewReader(body))
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("CreateVolume: wrong status.  Want %d. Got %d.", http.StatusCreated, recorder.Code)
+	}
+	var returned docker.Volume
+	err := json.NewDecoder(recorder.Body).Decode(&returned)
+	if err != nil {
+		t.Error(err)
+	}
+	if returned.Name != "test-volume" {
+		t.Errorf("CreateVolume: Name mismatch.  Expected: test-volume.  Returned %q.", returned.Name)
+	}
+	if returned.Driver != "local" {
+		t.Errorf("CreateVolume: Driver mismatch.  Expected: local.  Returned: %q", returned.Driver)
+	}
+	if returned.Mountpoint != "/var/lib/docker/volumes/test-volume" {
+		t.Errorf("CreateVolume:  Mountpoint mismatch.  Expected: /var/lib/docker/volumes/test-volume.  Returned: %q.", returned.Mountpoint)
+	}
+}
+
+func TestCreateVolumeAlreadExists(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	server.volStore = make(map[string]*volumeCounter)
+	server.volStore["test-volume"] = &volumeCounter{
+		volume: docker.Volume{
+			Name:       "test-volume",
+			Driver:     "local",
+			Mountpoint: "/var/lib/docker/volumes/test-volume",
+		},
+		count: 0,
+	}
+	body := `{"Name":"test-volume"}`
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/volumes/create", strings.NewReader(body))
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("CreateVolumeAlreadExists: wrong status.  Want %d. Got %d.", http.StatusCreated, recorder.Code)
+	}
+	var returned docker.Volume
+	err := json.NewDecoder(recorder.Body).Decode(&returned)
+	if err != nil {
+		t.Error(err)
+	}
+	if returned.Name != "test-volume" {
+		t.Errorf("CreateVolumeAlreadExists: Name mismatch.  Expected: test-volume.  Returned %q.", returned.Name)
+	}
+	if returned.Driver != "local" {
+		t.Errorf("CreateVolumeAlreadExists: Driver mismatch.  Expected: local.  Returned: %q", returned.Driver)
+	}
+	if returned.Mountpoint != "/var/lib/docker/volumes/test-volume" {
+		t.Errorf("CreateVolumeAlreadExists:  Mountpoint mismatch.  Expected: /var/lib/docker/volumes/test-volume.  Returned: %q.", returned.Mountpoint)
+	}
+}
+
+func TestInspectVolume(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	expected := docker.Volume{
+		Name:       "test-volume",
+		Driver:     "local",
+		Mountpoint: "/var/lib/docker/volumes/test-volume",
+	}
+	volC := &volumeCounter{
+		volume: expected,
+		count:  0,
+	}
+	volStore := make(map[string]*volumeCounter)
+	volStore["test-volume"] = volC
+	server.volStore = volStore
+	request, _ := http.NewRequest("GET", "/volumes/test-volume", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("InspectVolume: wrong status.  Want %d.  God %d.", http.StatusOK, recorder.Code)
+	}
+	var returned docker.Volume
+	err := json.NewDecoder(recorder.Body).Decode(&returned)
+	if err != nil {
+		t.Error(err)
+	}
+	if returned.Name != "test-volume" {
+		t.Errorf("InspectVolume: Name mismatch.  Expected: test-volume.  Returned %q.", returned.Name)
+	}
+	if returned.Driver != "local" {
+		t.Errorf("InspectVolume: Driver mismatch.  Expected: local.  Returned: %q", returned.Driver)
+	}
+	if returned.Mountpoint != "/var/lib/docker/volumes/test-volume" {
+		t.Errorf("InspectVolume:  Mountpoint mismatch.  Expected: /var/lib/docker/volumes/test-volume.  Returned: %q.", returned.Mountpoint)
+	}
+}
+
+func TestInspectVolumeNotFound(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/volumes/test-volume", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("RemoveMissingVolume: wrong status.  Want %d.  Got %d.", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestRemoveVolume(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	server.volStore = make(map[string]*volumeCounter)
+	server.volStore["test-volume"] = &volumeCounter{
+		volume: docker.Volume{
+			Name:       "test-volume",
+			Driver:     "local",
+			Mountpoint: "/var/lib/docker/volumes/test-volume",
+		},
+		count: 0,
+	}
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("DELETE", "/volumes/test-volume", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("RemoveVolume: wrong status.  Want %d.  Got %d.", http.StatusNoContent, recorder.Code)
+	}
+}
+
+func TestRemoveMissingVolume(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("DELETE", "/volumes/test-volume", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("RemoveMissingVolume: wrong status.  Want %d.  Got %d.", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestRemoveVolumeInuse(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	server.volStore = make(map[string]*volumeCounter)
+	server.volStore["test-volume"] = &volumeCounter{
+		volume: docker.Volume{
+			Name:       "test-volume",
+			Driver:     "local",
+			Mountpoint: "/var/lib/docker/volumes/test-volume",
+		},
+		count: 1,
+	}
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("DELETE", "/volumes/test-volume", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("RemoveVolume: wrong status.  Want %d.  Got %d.", http.StatusConflict, recorder.Code)
+	}
+}
+
+func TestUploadToContainer(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	cont := &docker.Container{
+		ID: "id123",
+		State: docker.State{
+			Running:  true,
+			ExitCode: 0,
+		},
+	}
+	server.containers = append(server.containers, cont)
+	server.uploadedFiles = make(map[string]string)
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("PUT", fmt.Sprintf("/containers/%s/archive?path=abcd", cont.ID), nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("UploadToContainer: wrong status. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+	if val, ok := server.uploadedFiles[cont.ID]; !ok {
+		t.Errorf("UploadToContainer: uploadedFiles should exist.")
+	} else if val != "abcd" {
+		t.Errorf("UploadToContainer: wrong uploadedFiles. Want 'abcd'. Got %s.", val)
+	}
+}
+
+func TestUploadToContainerWithBodyTarFile(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	cont := &docker.Container{
+		ID: "id123",
+		State: docker.State{
+			Running:  true,
+			ExitCode: 0,
+		},
+	}
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	defer tw.Close()
+	hdr := &tar.Header{
+		Name: "test.tar.gz",
+		Mode: 0600,
+		Size: int64(buf.Len()),
+	}
+	tw.WriteHeader(hdr)
+	tw.Write([]byte("something"))
+	tw.Close()
+	server.containers = append(server.containers, cont)
+	server.uploadedFiles = make(map[string]string)
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("PUT", fmt.Sprintf("/containers/%s/archive?path=abcd", cont.ID), buf)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("UploadToContainer: wrong status. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+	if val, ok := server.uploadedFiles[cont.ID]; !ok {
+		t.Errorf("UploadToContainer: uploadedFiles should exist.")
+	} else if val != "abcd/test.tar.gz" {
+		t.Errorf("UploadToContainer: wrong uploadedFiles. Want 'abcd/test.tar.gz'. Got %s.", val)
+	}
+}
+
+func TestUploadToContainerBodyNotTarFile(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	cont := &docker.Container{
+		ID: "id123",
+		State: docker.State{
+			Running:  true,
+			ExitCode: 0,
+		},
+	}
+	buf := bytes.NewBufferString("something")
+	server.containers = append(server.containers, cont)
+	server.uploadedFiles = make(map[string]string)
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("PUT", fmt.Sprintf("/containers/%s/archive?path=abcd", cont.ID), buf)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("UploadToContainer: wrong status. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+	if val, ok := server.uploadedFiles[cont.ID]; !ok {
+		t.Errorf("UploadToContainer: uploadedFiles should exist.")
+	} else if val != "abcd" {
+		t.Errorf("UploadToContainer: wrong uploadedFiles. Want 'abcd'. Got %s.", val)
+	}
+}
+
+func TestUploadToContainerMissingContainer(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("PUT", "/containers/missing-container/archive?path=abcd", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("UploadToContainer: wrong status. Want %d. Got %d.", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestInfoDocker(t *testing.T) {
+	t.Parallel()
+	server, _ := NewServer("127.0.0.1:0", nil, nil)
+	defer server.Stop()
+	addContainers(server, 1)
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/info", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("InfoDocker: wrong status. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+	var infoData map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &infoData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if infoData["Containers"].(float64) != 1.0 {
+		t.Fatalf("InfoDocker: wrong containers count. Want %f. Got %f.", 1.0, infoData["Containers"])
+	}
+	if infoData["DockerRootDir"].(string) != "/var/lib/docker" {
+		t.Fatalf("InfoDocker: wrong docker root. Want /var/lib/docker. Got %s.", infoData["DockerRootDir"])
+	}
+}
+
+func TestInfoDockerWithSwarm(t *testing.T) {
+	t.Parallel()
+	srv1, srv2 := setUpSwarm(t)
+	defer srv1.Stop()
+	defer srv2.Stop()
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/info", nil)
+	srv1.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("InfoDocker: wrong status. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+	var infoData docker.DockerInfo
+	err := json.Unmarshal(recorder.Body.Bytes(), &infoData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSwarm := swarm.Info{
+		NodeID: srv1.nodeID,
+		RemoteManagers: []swarm.Peer{
+			{NodeID: srv1.nodeID, Addr: srv1.SwarmAddress()},
+			{NodeID: srv2.nodeID, Addr: srv2.SwarmAddress()},
+		},
+	}
+	if !reflect.DeepEqual(infoData.Swarm, expectedSwarm) {
+		t.Fatalf("InfoDocker: wrong swarm info. Want:\n%#v\nGot:\n%#v", expectedSwarm, infoData.Swarm)
+	}
+}
+
+func TestVersionDocker(t *testing.T) {
+	t.Parallel()
+	server, _ := NewServer("127.0.0.1:0", nil, nil)
+	defer server.Stop()
+	server.buildMuxer()
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/version", nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("VersionDocker: wrong status. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+}
+
+func TestDownloadFromContainer(t *testing.T) {
+	t.Parallel()
+	server := DockerServer{}
+	server.buildMuxer()
+	cont := &docker.Container{
+		ID: "id123",
+		State: docker.State{
+			Running:  true,
+			ExitCode: 0,
+		},
+	}
+	server.containers = append(server.containers, cont)
+	server.uploadedFiles = make(map[string]string)
+	server.uploadedFiles[cont.ID] = "abcd"
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", fmt.Sprintf("/containers/%s/archive?path=abcd", cont.ID), nil)
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("DownloadFromContainer: wrong status. Want %d. Got %d.", http.StatusOK, recorder.Code)
+	}
+	if recorder.HeaderMap.Get("Content-Type") != "application/x-tar" {
+		t.Errorf("DownloadFromContainer: wrong Content-Type. Want 'application/x-tar'. Got %s.", recorder.HeaderMap.Get("Content-Type"))
+	}
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+// localBackend stores the cache archives in a directory, that has to be accessible
+// from the build environment, so it's useful mostly for shell and docker executors
+type localBackend struct {
+	path string
+}
+
+func (b *localBackend) getPath(objectName string) string {
+	return filepath.Join(b.path, filepath.FromSlash(objectName))
+}
+
+func (b *localBackend) GetDownloadURL(objectName string, expires time.Duration) (string, error) {
+	return b.getPath(objectName), nil
+}
+
+func (b *localBackend) GetUploadURL(objectName string, expires time.Duration) (string, error) {
+	return b.getPath(objectName), nil
+}
+
+func newLocalBackend(config *common.CacheConfig) (common.CacheBackend, error) {
+	if config.Path == "" {
+		return nil, errors.New("missing path of local cache")
+	}
+
+	path, err := filepath.Abs(config.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &localBackend{path: path}, nil
+}
+
+func init() {
+	common.RegisterCacheBackend("local", newLocalBackend)
+}
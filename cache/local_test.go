@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+func TestLocalBackend(t *testing.T) {
+	backend, err := common.NewCacheBackend(&common.CacheConfig{Type: "local", Path: "/cache"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	downloadURL, err := backend.GetDownloadURL("project/1/master.zip", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/cache/project/1/master.zip"), downloadURL)
+
+	uploadURL, err := backend.GetUploadURL("project/1/master.zip", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, downloadURL, uploadURL)
+}
+
+func TestLocalBackendRequiresPath(t *testing.T) {
+	_, err := common.NewCacheBackend(&common.CacheConfig{Type: "local"})
+	assert.Error(t, err)
+}
+
+func TestUnknownCacheBackend(t *testing.T) {
+	_, err := common.NewCacheBackend(&common.CacheConfig{Type: "unknown"})
+	assert.Error(t, err)
+
+	backend, err := common.NewCacheBackend(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, backend)
+}
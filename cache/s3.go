@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+const s3DefaultLocation = "us-east-1"
+
+// S3 doesn't allow the presigned URLs to be valid for longer than a week
+const s3MaxExpires = 7 * 24 * time.Hour
+
+// s3Backend stores the cache archives in S3 compatible storage,
+// the build environment accesses them with presigned URLs,
+// so the credentials are never exposed to the build
+type s3Backend struct {
+	config *common.CacheConfig
+	now    func() time.Time
+}
+
+func s3Escape(value string, escapeSlash bool) string {
+	var escaped []byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			escaped = append(escaped, c)
+		case c == '/' && !escapeSlash:
+			escaped = append(escaped, c)
+		default:
+			escaped = append(escaped, []byte(fmt.Sprintf("%%%02X", c))...)
+		}
+	}
+	return string(escaped)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	hash := hmac.New(sha256.New, key)
+	hash.Write([]byte(data))
+	return hash.Sum(nil)
+}
+
+func sha256Hex(data string) string {
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+// presignS3URL creates the URL signed with AWS Signature Version 4 passed in query string
+func presignS3URL(method, scheme, host, objectPath, region, accessKey, secretKey string, now time.Time, expires time.Duration) string {
+	now = now.UTC()
+	date := now.Format("20060102")
+	scope := date + "/" + region + "/s3/aws4_request"
+
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    accessKey + "/" + scope,
+		"X-Amz-Date":          now.Format("20060102T150405Z"),
+		"X-Amz-Expires":       fmt.Sprintf("%d", int(expires.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+
+	var keys []string
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var canonicalQuery []string
+	for _, key := range keys {
+		canonicalQuery = append(canonicalQuery, s3Escape(key, true)+"="+s3Escape(query[key], true))
+	}
+
+	canonicalPath := s3Escape(objectPath, false)
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalPath,
+		strings.Join(canonicalQuery, "&"),
+		"host:" + host,
+		"",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		query["X-Amz-Date"],
+		scope,
+		sha256Hex(canonicalRequest),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return scheme + "://" + host + canonicalPath + "?" + strings.Join(canonicalQuery, "&") + "&X-Amz-Signature=" + signature
+}
+
+func (b *s3Backend) presign(method, objectName string, expires time.Duration) (string, error) {
+	if expires > s3MaxExpires {
+		expires = s3MaxExpires
+	}
+
+	scheme := "https"
+	if b.config.Insecure {
+		scheme = "http"
+	}
+
+	location := b.config.BucketLocation
+	if location == "" {
+		location = s3DefaultLocation
+	}
+
+	// the path-style requests are used, as they are supported by all S3 compatible servers
+	objectPath := "/" + path.Join(b.config.BucketName, b.config.Path, objectName)
+
+	return presignS3URL(method, scheme, b.config.ServerAddress, objectPath, location,
+		b.config.AccessKey, b.config.SecretKey, b.now(), expires), nil
+}
+
+func (b *s3Backend) GetDownloadURL(objectName string, expires time.Duration) (string, error) {
+	return b.presign("GET", objectName, expires)
+}
+
+func (b *s3Backend) GetUploadURL(objectName string, expires time.Duration) (string, error) {
+	return b.presign("PUT", objectName, expires)
+}
+
+func newS3Backend(config *common.CacheConfig) (common.CacheBackend, error) {
+	if config.ServerAddress == "" || config.BucketName == "" {
+		return nil, errors.New("missing server address or bucket name of S3 cache")
+	}
+	if config.AccessKey == "" || config.SecretKey == "" {
+		return nil, errors.New("missing credentials of S3 cache")
+	}
+	if _, err := url.Parse("http://" + config.ServerAddress); err != nil {
+		return nil, fmt.Errorf("invalid server address of S3 cache: %v", err)
+	}
+	return &s3Backend{config: config, now: time.Now}, nil
+}
+
+func init() {
+	common.RegisterCacheBackend("s3", newS3Backend)
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers/archives"
+)
+
+const artifactsDownloadRetries = 3
+const artifactsDownloadRetryInterval = 5 * time.Second
+
+type ArtifactsDownloaderCommand struct {
+	common.BuildCredentials
+
+	Size   int64  `long:"size" description:"The expected size of artifacts archive"`
+	SHA256 string `long:"sha256" description:"The expected SHA256 checksum of artifacts archive"`
+}
+
+func (c *ArtifactsDownloaderCommand) download(archiveFile string) bool {
+	for retry := 0; retry < artifactsDownloadRetries; retry++ {
+		if retry > 0 {
+			log.Warningln("Retrying in", artifactsDownloadRetryInterval, "...")
+			time.Sleep(artifactsDownloadRetryInterval)
+		}
+
+		switch common.DownloadArtifacts(c.BuildCredentials, archiveFile) {
+		case common.DownloadSucceeded:
+			return true
+		case common.DownloadForbidden, common.DownloadNotFound:
+			// retrying will not help
+			return false
+		}
+	}
+	return false
+}
+
+func (c *ArtifactsDownloaderCommand) verify(archiveFile string) error {
+	file, err := os.Open(archiveFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+
+	if c.Size > 0 && size != c.Size {
+		return fmt.Errorf("size mismatch: expected %d bytes, got %d bytes", c.Size, size)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if c.SHA256 != "" && checksum != c.SHA256 {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", c.SHA256, checksum)
+	}
+
+	log.Infoln("Downloaded", size, "bytes, SHA256", checksum)
+	return nil
+}
+
+func (c *ArtifactsDownloaderCommand) run() error {
+	file, err := ioutil.TempFile("", "artifacts")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	if !c.download(file.Name()) {
+		return fmt.Errorf("failed to download artifacts of build %d", c.ID)
+	}
+
+	err = c.verify(file.Name())
+	if err != nil {
+		return err
+	}
+	return archives.ExtractZipArchive(file.Name())
+}
+
+func (c *ArtifactsDownloaderCommand) Execute(context *cli.Context) {
+	if c.URL == "" || c.Token == "" || c.ID <= 0 {
+		log.Fatalln("Missing build credentials, the --url, --token and --id are required")
+	}
+
+	err := c.run()
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func init() {
+	common.RegisterCommand2("artifacts-downloader", "download and extract build artifacts (internal)", &ArtifactsDownloaderCommand{})
+}
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers/archives"
+)
+
+const artifactsUploadRetries = 3
+const artifactsUploadRetryInterval = 5 * time.Second
+
+type ArtifactsUploaderCommand struct {
+	common.BuildCredentials
+	fileArchiver
+
+	Name     string `long:"name" description:"The name of the archive"`
+	ExpireIn string `long:"expire-in" description:"When to expire artifacts"`
+	MaxSize  int64  `long:"max-size" description:"Maximum size of the archive in megabytes, 0 means no limit"`
+}
+
+func (c *ArtifactsUploaderCommand) createArchive(files []string) (string, error) {
+	// the archive is created in its own directory,
+	// because the name of the uploaded file is used as the name of artifacts
+	tempDir, err := ioutil.TempDir("", "artifacts")
+	if err != nil {
+		return "", err
+	}
+
+	name := c.Name
+	if name == "" {
+		name = "artifacts"
+	}
+
+	archiveFile := filepath.Join(tempDir, name+".zip")
+	file, err := os.Create(archiveFile)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", err
+	}
+	defer file.Close()
+
+	err = archives.CreateZipArchive(file, files)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", err
+	}
+	return archiveFile, nil
+}
+
+func (c *ArtifactsUploaderCommand) upload(archiveFile string) bool {
+	fi, err := os.Stat(archiveFile)
+	if err != nil {
+		log.Errorln("Failed to read artifacts archive:", err)
+		return false
+	}
+
+	if c.MaxSize > 0 && fi.Size() > c.MaxSize*1024*1024 {
+		log.Errorln("The artifacts archive has", fi.Size(), "bytes, which exceeds the maximum of", c.MaxSize, "MB")
+		return false
+	}
+
+	for retry := 0; retry < artifactsUploadRetries; retry++ {
+		if retry > 0 {
+			log.Warningln("Retrying in", artifactsUploadRetryInterval, "...")
+			time.Sleep(artifactsUploadRetryInterval)
+		}
+
+		switch common.UploadArtifacts(c.BuildCredentials, archiveFile, c.ExpireIn) {
+		case common.UploadSucceeded:
+			return true
+		case common.UploadForbidden, common.UploadTooLarge, common.UploadRejected:
+			// retrying will not help
+			return false
+		}
+	}
+	return false
+}
+
+func (c *ArtifactsUploaderCommand) Execute(context *cli.Context) {
+	if c.URL == "" || c.Token == "" || c.ID <= 0 {
+		log.Fatalln("Missing build credentials, the --url, --token and --id are required")
+	}
+
+	files := c.enumerate()
+	if len(files) == 0 {
+		log.Warningln("No files to upload")
+		return
+	}
+
+	archiveFile, err := c.createArchive(files)
+	if err != nil {
+		log.Fatalln("Failed to create artifacts archive:", err)
+	}
+
+	fi, err := os.Stat(archiveFile)
+	if err == nil {
+		log.Infoln("Uploading", len(files), "files,", fi.Size(), "bytes")
+	}
+
+	uploaded := c.upload(archiveFile)
+	os.RemoveAll(filepath.Dir(archiveFile))
+	if !uploaded {
+		log.Fatalln("Failed to upload artifacts")
+	}
+}
+
+func init() {
+	common.RegisterCommand2("artifacts-uploader", "create and upload build artifacts (internal)", &ArtifactsUploaderCommand{})
+}
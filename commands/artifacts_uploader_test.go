@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+func newArtifactsUploader(statusCode int, requests *int) (*ArtifactsUploaderCommand, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.WriteHeader(statusCode)
+	}))
+
+	cmd := &ArtifactsUploaderCommand{
+		BuildCredentials: common.BuildCredentials{
+			ID:    1,
+			Token: "token",
+			URL:   server.URL,
+		},
+	}
+	return cmd, server.Close
+}
+
+func writeTestArchive(t *testing.T, size int) string {
+	file, err := ioutil.TempFile("", "artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(make([]byte, size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file.Name()
+}
+
+func TestArtifactsUploaderRejectsTooLargeArchive(t *testing.T) {
+	requests := 0
+	cmd, cleanup := newArtifactsUploader(201, &requests)
+	defer cleanup()
+
+	archiveFile := writeTestArchive(t, 2*1024*1024)
+	defer os.Remove(archiveFile)
+
+	cmd.MaxSize = 1
+	assert.False(t, cmd.upload(archiveFile))
+	assert.Equal(t, 0, requests)
+
+	cmd.MaxSize = 2
+	assert.True(t, cmd.upload(archiveFile))
+	assert.Equal(t, 1, requests)
+}
+
+func TestArtifactsUploaderDoesntRetryClientErrors(t *testing.T) {
+	requests := 0
+	cmd, cleanup := newArtifactsUploader(400, &requests)
+	defer cleanup()
+
+	archiveFile := writeTestArchive(t, 10)
+	defer os.Remove(archiveFile)
+
+	assert.False(t, cmd.upload(archiveFile))
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers/archives"
+)
+
+type CacheArchiverCommand struct {
+	fileArchiver
+
+	URL string `long:"url" description:"URL or local path where the cache archive is stored"`
+}
+
+func isRemoteCacheURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+func (c *CacheArchiverCommand) upload(archiveFile string) error {
+	file, err := os.Open(archiveFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("PUT", c.URL, file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = fi.Size()
+	req.Header.Set("Content-Type", "application/zip")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("received: %s", res.Status)
+	}
+	return nil
+}
+
+func (c *CacheArchiverCommand) store(archiveFile string) error {
+	// the cache can be restored by builds running as different users
+	err := os.Chmod(archiveFile, 0644)
+	if err != nil {
+		return err
+	}
+
+	// the concurrent builds can't see partially written archive
+	return os.Rename(archiveFile, c.URL)
+}
+
+func (c *CacheArchiverCommand) createArchive(files []string) (string, error) {
+	// the local archive is created next to the cache, so it can be renamed to it
+	dir := ""
+	if !isRemoteCacheURL(c.URL) {
+		dir = filepath.Dir(c.URL)
+		os.MkdirAll(dir, 0755)
+	}
+
+	file, err := ioutil.TempFile(dir, "cache")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	err = archives.CreateZipArchive(file, files)
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+func (c *CacheArchiverCommand) run() error {
+	files := c.enumerate()
+	if len(files) == 0 {
+		log.Warningln("No files to cache")
+		return nil
+	}
+
+	archiveFile, err := c.createArchive(files)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archiveFile)
+
+	if isRemoteCacheURL(c.URL) {
+		err = c.upload(archiveFile)
+	} else {
+		err = c.store(archiveFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Infoln("Cached", len(files), "files")
+	return nil
+}
+
+func (c *CacheArchiverCommand) Execute(context *cli.Context) {
+	if c.URL == "" {
+		log.Fatalln("Missing --url")
+	}
+
+	// failing to store the cache doesn't fail the build
+	err := c.run()
+	if err != nil {
+		log.Warningln("Failed to create cache:", err)
+	}
+}
+
+func init() {
+	common.RegisterCommand2("cache-archiver", "create and store cache archive (internal)", &CacheArchiverCommand{})
+}
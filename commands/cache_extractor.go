@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers/archives"
+)
+
+type CacheExtractorCommand struct {
+	URL string `long:"url" description:"URL or local path where the cache archive is stored"`
+}
+
+// download returns false when there's no cache yet
+func (c *CacheExtractorCommand) download(archiveFile string) (bool, error) {
+	res, err := http.Get(c.URL)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return false, nil
+	case res.StatusCode/100 != 2:
+		return false, fmt.Errorf("received: %s", res.Status)
+	}
+
+	file, err := os.Create(archiveFile)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, res.Body)
+	return err == nil, err
+}
+
+func (c *CacheExtractorCommand) run() error {
+	archiveFile := c.URL
+
+	if isRemoteCacheURL(c.URL) {
+		file, err := ioutil.TempFile("", "cache")
+		if err != nil {
+			return err
+		}
+		file.Close()
+		defer os.Remove(file.Name())
+
+		found, err := c.download(file.Name())
+		if err != nil {
+			return err
+		} else if !found {
+			log.Infoln("No cache found")
+			return nil
+		}
+		archiveFile = file.Name()
+	} else if _, err := os.Stat(archiveFile); os.IsNotExist(err) {
+		log.Infoln("No cache found")
+		return nil
+	}
+
+	err := archives.ExtractZipArchive(archiveFile)
+	if err != nil {
+		return err
+	}
+
+	log.Infoln("Cache restored")
+	return nil
+}
+
+func (c *CacheExtractorCommand) Execute(context *cli.Context) {
+	if c.URL == "" {
+		log.Fatalln("Missing --url")
+	}
+
+	// failing to restore the cache doesn't fail the build
+	err := c.run()
+	if err != nil {
+		log.Warningln("Failed to restore cache:", err)
+	}
+}
+
+func init() {
+	common.RegisterCommand2("cache-extractor", "download and extract cache archive (internal)", &CacheExtractorCommand{})
+}
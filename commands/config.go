@@ -3,10 +3,12 @@ package commands
 import (
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
 	"os"
+	"sync"
 )
 
 type configOptions struct {
-	config *common.Config
+	config      *common.Config
+	configMutex sync.Mutex
 
 	ConfigFile string `short:"c" long:"config" env:"CONFIG_FILE" description:"Config file"`
 }
@@ -21,10 +23,25 @@ func (c *configOptions) loadConfig() error {
 	if err != nil {
 		return err
 	}
+	c.configMutex.Lock()
 	c.config = config
+	c.configMutex.Unlock()
 	return nil
 }
 
+// getConfig returns a copy of the loaded config,
+// so it can be safely read while the config is being reloaded
+func (c *configOptions) getConfig() *common.Config {
+	c.configMutex.Lock()
+	defer c.configMutex.Unlock()
+
+	if c.config == nil {
+		return nil
+	}
+	config := *c.config
+	return &config
+}
+
 func (c *configOptions) touchConfig() error {
 	// try to load existing config
 	err := c.loadConfig()
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+
+	log "github.com/sirupsen/logrus"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors/docker"
+)
+
+const defaultDockerCleanupMaxAge = 3 * time.Hour
+const defaultDockerCleanupCacheMaxAge = 7 * 24 * time.Hour
+
+type DockerCleanupCommand struct {
+	configOptions
+	docker.CleanupOptions
+}
+
+func cleanupDockerRunners(runners []*common.RunnerConfig, options docker.CleanupOptions, isBuildRunning func(runner *common.RunnerConfig, buildID int) bool) {
+	for _, runner := range runners {
+		if !strings.HasPrefix(runner.Executor, "docker") {
+			continue
+		}
+
+		runnerOptions := options
+		if isBuildRunning != nil {
+			runner := runner
+			runnerOptions.IsBuildRunning = func(buildID int) bool {
+				return isBuildRunning(runner, buildID)
+			}
+		}
+
+		removed, err := docker.Cleanup(runner, runnerOptions)
+		if err != nil {
+			log.WithField("runner", runner.ShortDescription()).Errorln("Failed to cleanup containers:", err)
+		} else if removed > 0 {
+			log.WithField("runner", runner.ShortDescription()).Println("Cleaned up", removed, "containers")
+		}
+	}
+}
+
+func (c *DockerCleanupCommand) Execute(context *cli.Context) {
+	err := c.loadConfig()
+	if err != nil {
+		log.Fatalln(err)
+		return
+	}
+
+	cleanupDockerRunners(c.config.Runners, c.CleanupOptions, nil)
+}
+
+func init() {
+	common.RegisterCommand2("docker-cleanup", "remove orphaned containers and unused caches of docker runners", &DockerCleanupCommand{
+		CleanupOptions: docker.CleanupOptions{
+			MaxAge:      defaultDockerCleanupMaxAge,
+			CacheMaxAge: defaultDockerCleanupCacheMaxAge,
+		},
+	})
+}
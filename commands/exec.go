@@ -0,0 +1,265 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v1"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+const ciConfigFile = ".gitlab-ci.yml"
+
+// the global keywords of .gitlab-ci.yml that can't be used as job names
+var ciReservedKeywords = []string{"image", "services", "stages", "types", "variables", "before_script", "after_script", "cache"}
+
+// the options that can be defined globally and overwritten by job
+var ciJobOptions = []string{"image", "services", "before_script", "after_script", "cache"}
+
+type ExecCommand struct {
+	common.RunnerConfig
+
+	Timeout int `long:"timeout" description:"Build timeout in seconds"`
+}
+
+// convertYAML converts the maps of parsed YAML to the format sent by coordinator
+func convertYAML(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{})
+		for key, item := range value {
+			result[fmt.Sprint(key)] = convertYAML(item)
+		}
+		return result
+
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for idx, item := range value {
+			result[idx] = convertYAML(item)
+		}
+		return result
+
+	default:
+		return value
+	}
+}
+
+func loadCIConfig(fileName string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var config map[interface{}]interface{}
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return convertYAML(config).(map[string]interface{}), nil
+}
+
+func getCIJob(config map[string]interface{}, name string) (map[string]interface{}, error) {
+	for _, keyword := range ciReservedKeywords {
+		if name == keyword {
+			return nil, fmt.Errorf("%s is not a job", name)
+		}
+	}
+	if strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("%s is a hidden job", name)
+	}
+
+	job, ok := config[name].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("no job named %s in %s", name, ciConfigFile)
+	}
+	if _, ok := job["script"]; !ok {
+		return nil, fmt.Errorf("job %s doesn't have script", name)
+	}
+	return job, nil
+}
+
+func getCIVariables(values ...interface{}) (variables []common.BuildVariable) {
+	for _, value := range values {
+		definitions, _ := value.(map[string]interface{})
+
+		var keys []string
+		for key := range definitions {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			variables = append(variables, common.BuildVariable{
+				Key:    key,
+				Value:  fmt.Sprint(definitions[key]),
+				Public: true,
+			})
+		}
+	}
+	return
+}
+
+func gitOutput(repoDir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// createBuild synthesizes the build, as it would be sent by coordinator,
+// the sources are cloned from the repository in working directory
+func (c *ExecCommand) createBuild(repoDir string, config map[string]interface{}, jobName string) (*common.GetBuildResponse, error) {
+	job, err := getCIJob(config, jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	commands, ok := common.BuildOptions(job).GetCommands("script")
+	if !ok {
+		return nil, fmt.Errorf("job %s has invalid script", jobName)
+	}
+
+	sha, err := gitOutput(repoDir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	refName, err := gitOutput(repoDir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	// the detached HEAD has no branch name
+	if refName == "HEAD" {
+		refName = sha
+	}
+
+	options := common.BuildOptions{}
+	for _, key := range ciJobOptions {
+		if value, ok := job[key]; ok {
+			options[key] = value
+		} else if value, ok := config[key]; ok {
+			options[key] = value
+		}
+	}
+
+	if _, ok := job["artifacts"]; ok {
+		log.Warningln("The artifacts are not uploaded without coordinator")
+	}
+
+	stage, _ := job["stage"].(string)
+	if stage == "" {
+		stage = "test"
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = common.DefaultTimeout
+	}
+
+	return &common.GetBuildResponse{
+		ID:        1,
+		Name:      jobName,
+		Stage:     stage,
+		Commands:  commands,
+		RepoURL:   repoDir,
+		Sha:       sha,
+		RefName:   refName,
+		Timeout:   timeout,
+		Variables: getCIVariables(config["variables"], job["variables"]),
+		Options:   options,
+	}, nil
+}
+
+func (c *ExecCommand) run(executorName, jobName string) error {
+	repoDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	config, err := loadCIConfig(ciConfigFile)
+	if err != nil {
+		return err
+	}
+
+	buildData, err := c.createBuild(repoDir, config, jobName)
+	if err != nil {
+		return err
+	}
+
+	c.Executor = executorName
+	if executorName == "shell" && c.BuildsDir == nil {
+		// don't clone the sources into working directory
+		buildsDir, err := ioutil.TempDir("", "gitlab-runner-exec")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(buildsDir)
+		c.BuildsDir = &buildsDir
+	} else if strings.HasPrefix(executorName, "docker") {
+		// the helper container clones the sources from the repository on host
+		if c.Docker == nil {
+			c.Docker = &common.DockerConfig{}
+		}
+		c.Docker.Volumes = append(c.Docker.Volumes, repoDir+":"+repoDir+":ro")
+	}
+
+	ctx, abortBuild := context.WithCancel(context.Background())
+	defer abortBuild()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case interrupt := <-signals:
+			log.Warningln("Requested exit:", interrupt)
+			abortBuild()
+		case <-ctx.Done():
+		}
+	}()
+
+	build := &common.Build{
+		GetBuildResponse: *buildData,
+		Runner:           &c.RunnerConfig,
+		LocalTrace:       os.Stdout,
+	}
+	build.AssignID()
+
+	err = build.Run(ctx, common.NewConfig())
+	if err != nil {
+		return err
+	}
+	if build.BuildState != common.Success {
+		return errors.New("build failed")
+	}
+	return nil
+}
+
+func (c *ExecCommand) Execute(context *cli.Context) {
+	args := context.Args()
+	if len(args) != 2 {
+		log.Fatalln("Usage: exec <executor> <job>")
+	}
+
+	err := c.run(args[0], args[1])
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func init() {
+	common.RegisterCommand2("exec", "execute a build locally from "+ciConfigFile, &ExecCommand{})
+}
@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+var execTestConfig = map[string]interface{}{
+	"image":         "ruby:2.1",
+	"before_script": []interface{}{"bundle install"},
+	"variables":     map[string]interface{}{"DB": "postgres", "RAILS_ENV": "test"},
+	"rspec": map[string]interface{}{
+		"script":    []interface{}{"rspec"},
+		"variables": map[string]interface{}{"RAILS_ENV": "production", "WORKERS": 4},
+	},
+	"deploy": map[string]interface{}{
+		"stage":         "deploy",
+		"image":         "ruby:2.2",
+		"before_script": []interface{}{},
+		"script":        "cap deploy",
+	},
+	"no-script": map[string]interface{}{
+		"image": "alpine",
+	},
+	".hidden": map[string]interface{}{
+		"script": []interface{}{"true"},
+	},
+}
+
+func TestGetCIJob(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"rspec", true},
+		{"deploy", true},
+		{"no-script", false},
+		{".hidden", false},
+		{"image", false},
+		{"before_script", false},
+		{"variables", false},
+		{"missing", false},
+	}
+
+	for _, test := range tests {
+		job, err := getCIJob(execTestConfig, test.name)
+		if test.valid {
+			assert.NoError(t, err, test.name)
+			assert.NotNil(t, job, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestGetCIVariables(t *testing.T) {
+	tests := []struct {
+		values   []interface{}
+		expected []common.BuildVariable
+	}{
+		{
+			values:   []interface{}{nil, nil},
+			expected: nil,
+		},
+		{
+			values: []interface{}{map[string]interface{}{"B": "2", "A": 1}},
+			expected: []common.BuildVariable{
+				{Key: "A", Value: "1", Public: true},
+				{Key: "B", Value: "2", Public: true},
+			},
+		},
+		{
+			// the variables of job are defined after the global ones, so they overwrite them
+			values: []interface{}{
+				execTestConfig["variables"],
+				execTestConfig["rspec"].(map[string]interface{})["variables"],
+			},
+			expected: []common.BuildVariable{
+				{Key: "DB", Value: "postgres", Public: true},
+				{Key: "RAILS_ENV", Value: "test", Public: true},
+				{Key: "RAILS_ENV", Value: "production", Public: true},
+				{Key: "WORKERS", Value: "4", Public: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, getCIVariables(test.values...))
+	}
+}
+
+func git(t *testing.T, repoDir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+}
+
+func newExecTestRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir, err := ioutil.TempDir("", "exec-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	git(t, repoDir, "init", "-q")
+	git(t, repoDir, "checkout", "-q", "-b", "feature")
+	git(t, repoDir, "commit", "-q", "--allow-empty", "-m", "initial")
+	return repoDir
+}
+
+func TestCreateBuild(t *testing.T) {
+	repoDir := newExecTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	sha, err := gitOutput(repoDir, "rev-parse", "HEAD")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		job           string
+		timeout       int
+		stage         string
+		commands      string
+		image         interface{}
+		beforeScript  interface{}
+		expectedError bool
+	}{
+		{
+			job:          "rspec",
+			stage:        "test",
+			commands:     "rspec",
+			image:        "ruby:2.1",
+			beforeScript: []interface{}{"bundle install"},
+		},
+		{
+			job:          "deploy",
+			timeout:      60,
+			stage:        "deploy",
+			commands:     "cap deploy",
+			image:        "ruby:2.2",
+			beforeScript: []interface{}{},
+		},
+		{
+			job:           "no-script",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		c := &ExecCommand{Timeout: test.timeout}
+		build, err := c.createBuild(repoDir, execTestConfig, test.job)
+		if test.expectedError {
+			assert.Error(t, err, test.job)
+			continue
+		}
+		if !assert.NoError(t, err, test.job) {
+			continue
+		}
+
+		expectedTimeout := test.timeout
+		if expectedTimeout == 0 {
+			expectedTimeout = common.DefaultTimeout
+		}
+
+		assert.Equal(t, test.job, build.Name)
+		assert.Equal(t, test.stage, build.Stage)
+		assert.Equal(t, test.commands, build.Commands)
+		assert.Equal(t, repoDir, build.RepoURL)
+		assert.Equal(t, sha, build.Sha)
+		assert.Equal(t, "feature", build.RefName)
+		assert.Equal(t, expectedTimeout, build.Timeout)
+		assert.Equal(t, test.image, build.Options["image"])
+		assert.Equal(t, test.beforeScript, build.Options["before_script"])
+	}
+}
+
+func TestCreateBuildWithDetachedHead(t *testing.T) {
+	repoDir := newExecTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	git(t, repoDir, "checkout", "-q", "--detach")
+
+	build, err := (&ExecCommand{}).createBuild(repoDir, execTestConfig, "rspec")
+	if assert.NoError(t, err) {
+		assert.Equal(t, build.Sha, build.RefName)
+	}
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type fileArchiver struct {
+	Paths     []string `long:"path" description:"Add paths to archive, wildcards are supported"`
+	Untracked bool     `long:"untracked" description:"Add git untracked files"`
+
+	files map[string]os.FileInfo
+}
+
+func isPathInWorkingDirectory(path string) bool {
+	path = filepath.Clean(path)
+	return !filepath.IsAbs(path) && path != ".." && !strings.HasPrefix(path, ".."+string(filepath.Separator))
+}
+
+func (c *fileArchiver) add(path string) error {
+	// the paths outside of working directory are not archived
+	if !isPathInWorkingDirectory(path) {
+		log.Warningln(path, "is outside of working directory, skipping")
+		return nil
+	}
+
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	c.files[filepath.Clean(path)] = fi
+	return nil
+}
+
+func (c *fileArchiver) processPath(path string) {
+	matches, err := filepath.Glob(path)
+	if err != nil {
+		log.Warningln(path, err)
+		return
+	}
+
+	if len(matches) == 0 {
+		log.Warningln(path, "no matching files")
+		return
+	}
+
+	for _, match := range matches {
+		err = filepath.Walk(match, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			return c.add(path)
+		})
+		if err != nil {
+			log.Warningln(match, err)
+		}
+	}
+}
+
+func (c *fileArchiver) processUntracked() {
+	var output bytes.Buffer
+	cmd := exec.Command("git", "ls-files", "-o", "-z")
+	cmd.Stdout = &output
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if err != nil {
+		log.Warningln("Failed to list untracked files:", err)
+		return
+	}
+
+	for _, path := range strings.Split(output.String(), "\x00") {
+		if path == "" {
+			continue
+		}
+
+		err = c.add(path)
+		if err != nil {
+			log.Warningln(path, err)
+		}
+	}
+}
+
+// enumerate returns the sorted list of matched files relative to working directory
+func (c *fileArchiver) enumerate() []string {
+	c.files = make(map[string]os.FileInfo)
+
+	for _, path := range c.Paths {
+		c.processPath(path)
+	}
+
+	if c.Untracked {
+		c.processUntracked()
+	}
+
+	var files []string
+	for file := range c.files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
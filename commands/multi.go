@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"runtime"
@@ -11,11 +12,12 @@ import (
 	service "github.com/ayufan/golang-kardianos-service"
 	"github.com/codegangsta/cli"
 
-	log "github.com/Sirupsen/logrus"
+	log "github.com/sirupsen/logrus"
 
 	"errors"
 	"fmt"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors/docker"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers"
 	"math"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers/service"
@@ -34,17 +36,28 @@ type RunCommand struct {
 	User             string `short:"u" long:"user" description:"Use specific user to execute shell scripts"`
 	Syslog           bool   `long:"syslog" description:"Log to syslog"`
 
-	builds          []*common.Build
+	MetricsListenAddress string `long:"metrics-listen-address" env:"METRICS_LISTEN_ADDRESS" description:"Address to listen for Prometheus metrics requests, eg. :9252"`
+
+	DockerCleanupInterval time.Duration         `long:"docker-cleanup-interval" env:"DOCKER_CLEANUP_INTERVAL" description:"Periodically remove orphaned containers and unused caches of docker runners, eg. 1h"`
+	DockerCleanup         docker.CleanupOptions `namespace:"docker-cleanup"`
+
+	builds          []*runningBuild
 	buildsLock      sync.RWMutex
+	buildsAborted   bool
 	healthy         map[string]*RunnerHealth
 	healthyLock     sync.Mutex
 	finished        bool
-	abortBuilds     chan os.Signal
 	interruptSignal chan os.Signal
 	reloadSignal    chan os.Signal
 	doneSignal      chan int
 }
 
+// runningBuild is the build processed by worker together with the function aborting it
+type runningBuild struct {
+	*common.Build
+	abort context.CancelFunc
+}
+
 func (mr *RunCommand) errorln(args ...interface{}) {
 	args = append([]interface{}{len(mr.builds)}, args...)
 	log.Errorln(args...)
@@ -82,8 +95,20 @@ func (mr *RunCommand) getHealth(runner *common.RunnerConfig) *RunnerHealth {
 	return health
 }
 
+func (mr *RunCommand) getHealthFailures(runner *common.RunnerConfig) int {
+	health := mr.getHealth(runner)
+
+	mr.healthyLock.Lock()
+	defer mr.healthyLock.Unlock()
+	return health.failures
+}
+
 func (mr *RunCommand) isHealthy(runner *common.RunnerConfig) bool {
 	health := mr.getHealth(runner)
+
+	mr.healthyLock.Lock()
+	defer mr.healthyLock.Unlock()
+
 	if health.failures < common.HealthyChecks {
 		return true
 	}
@@ -100,26 +125,46 @@ func (mr *RunCommand) isHealthy(runner *common.RunnerConfig) bool {
 
 func (mr *RunCommand) makeHealthy(runner *common.RunnerConfig) {
 	health := mr.getHealth(runner)
+
+	mr.healthyLock.Lock()
+	defer mr.healthyLock.Unlock()
+
 	health.failures = 0
 	health.lastCheck = time.Now()
 }
 
 func (mr *RunCommand) makeUnhealthy(runner *common.RunnerConfig) {
 	health := mr.getHealth(runner)
-	health.failures++
 
+	mr.healthyLock.Lock()
+	defer mr.healthyLock.Unlock()
+
+	health.failures++
 	if health.failures >= common.HealthyChecks {
 		mr.errorln("Runner", runner.ShortDescription(), "is not healthy and will be disabled!")
 	}
 }
 
-func (mr *RunCommand) addBuild(newBuild *common.Build) {
+// addBuild registers the build and returns the context that is canceled when the build is aborted
+func (mr *RunCommand) addBuild(newBuild *common.Build) context.Context {
 	mr.buildsLock.Lock()
 	defer mr.buildsLock.Unlock()
 
-	newBuild.AssignID(mr.builds...)
-	mr.builds = append(mr.builds, newBuild)
+	otherBuilds := make([]*common.Build, 0, len(mr.builds))
+	for _, build := range mr.builds {
+		otherBuilds = append(otherBuilds, build.Build)
+	}
+	newBuild.AssignID(otherBuilds...)
+
+	ctx, abort := context.WithCancel(context.Background())
+	mr.builds = append(mr.builds, &runningBuild{Build: newBuild, abort: abort})
 	mr.debugln("Added a new build", newBuild)
+
+	// the build received while aborting the others is aborted as well
+	if mr.buildsAborted {
+		abort()
+	}
+	return ctx
 }
 
 func (mr *RunCommand) removeBuild(deleteBuild *common.Build) bool {
@@ -127,7 +172,8 @@ func (mr *RunCommand) removeBuild(deleteBuild *common.Build) bool {
 	defer mr.buildsLock.Unlock()
 
 	for idx, build := range mr.builds {
-		if build == deleteBuild {
+		if build.Build == deleteBuild {
+			build.abort()
 			mr.builds = append(mr.builds[0:idx], mr.builds[idx+1:]...)
 			mr.debugln("Build removed", deleteBuild)
 			return true
@@ -136,6 +182,16 @@ func (mr *RunCommand) removeBuild(deleteBuild *common.Build) bool {
 	return false
 }
 
+func (mr *RunCommand) abortBuilds() {
+	mr.buildsLock.Lock()
+	defer mr.buildsLock.Unlock()
+
+	mr.buildsAborted = true
+	for _, build := range mr.builds {
+		build.abort()
+	}
+}
+
 func (mr *RunCommand) buildsForRunner(runner *common.RunnerConfig) int {
 	count := 0
 	for _, build := range mr.builds {
@@ -146,6 +202,32 @@ func (mr *RunCommand) buildsForRunner(runner *common.RunnerConfig) int {
 	return count
 }
 
+func (mr *RunCommand) isBuildRunning(runner *common.RunnerConfig, buildID int) bool {
+	mr.buildsLock.RLock()
+	defer mr.buildsLock.RUnlock()
+
+	for _, build := range mr.builds {
+		if build.ID == buildID && build.Runner.ShortDescription() == runner.ShortDescription() {
+			return true
+		}
+	}
+	return false
+}
+
+func (mr *RunCommand) runDockerCleanup(stopCleanup chan bool) {
+	for {
+		select {
+		case <-time.After(mr.DockerCleanupInterval):
+			config := mr.getConfig()
+			if config != nil {
+				cleanupDockerRunners(config.Runners, mr.DockerCleanup, mr.isBuildRunning)
+			}
+		case <-stopCleanup:
+			return
+		}
+	}
+}
+
 func (mr *RunCommand) requestBuild(runner *common.RunnerConfig) *common.Build {
 	if runner == nil {
 		return nil
@@ -176,7 +258,6 @@ func (mr *RunCommand) requestBuild(runner *common.RunnerConfig) *common.Build {
 	newBuild := &common.Build{
 		GetBuildResponse: *buildData,
 		Runner:           runner,
-		BuildAbort:       mr.abortBuilds,
 	}
 	return newBuild
 }
@@ -184,7 +265,7 @@ func (mr *RunCommand) requestBuild(runner *common.RunnerConfig) *common.Build {
 func (mr *RunCommand) feedRunners(runners chan *common.RunnerConfig) {
 	for !mr.finished {
 		mr.debugln("Feeding runners to channel")
-		config := mr.config
+		config := mr.getConfig()
 		for _, runner := range config.Runners {
 			runners <- runner
 		}
@@ -203,8 +284,8 @@ func (mr *RunCommand) processRunners(id int, stopWorker chan bool, runners chan
 				break
 			}
 
-			mr.addBuild(newJob)
-			newJob.Run(mr.config)
+			ctx := mr.addBuild(newJob)
+			newJob.Run(ctx, mr.getConfig())
 			mr.removeBuild(newJob)
 			newJob = nil
 
@@ -234,16 +315,19 @@ func (mr *RunCommand) loadConfig() error {
 
 	// pass user to execute scripts as specific user
 	if mr.User != "" {
+		mr.configMutex.Lock()
 		mr.config.User = &mr.User
+		mr.configMutex.Unlock()
 	}
 
+	mr.healthyLock.Lock()
 	mr.healthy = nil
+	mr.healthyLock.Unlock()
 	return nil
 }
 
 func (mr *RunCommand) Start(s service.Service) error {
-	mr.builds = []*common.Build{}
-	mr.abortBuilds = make(chan os.Signal)
+	mr.builds = []*runningBuild{}
 	mr.interruptSignal = make(chan os.Signal, 1)
 	mr.reloadSignal = make(chan os.Signal, 1)
 	mr.doneSignal = make(chan int, 1)
@@ -262,6 +346,11 @@ func (mr *RunCommand) Start(s service.Service) error {
 		panic(err)
 	}
 
+	err = mr.serveMetrics()
+	if err != nil {
+		return err
+	}
+
 	// Start should not block. Do the actual work async.
 	go mr.Run()
 
@@ -276,6 +365,12 @@ func (mr *RunCommand) Run() {
 	stopWorker := make(chan bool)
 	go mr.startWorkers(startWorker, stopWorker, runners)
 
+	if mr.DockerCleanupInterval > 0 {
+		stopCleanup := make(chan bool)
+		go mr.runDockerCleanup(stopCleanup)
+		defer close(stopCleanup)
+	}
+
 	signal.Notify(mr.reloadSignal, syscall.SIGHUP)
 	signal.Notify(mr.interruptSignal, syscall.SIGQUIT)
 
@@ -286,7 +381,7 @@ func (mr *RunCommand) Run() {
 
 finish_worker:
 	for {
-		buildLimit := mr.config.Concurrent
+		buildLimit := mr.getConfig().Concurrent
 
 		for currentWorkers > buildLimit {
 			select {
@@ -315,7 +410,7 @@ finish_worker:
 				break
 			}
 
-			if !mr.config.ModTime.Before(info.ModTime()) {
+			if !mr.getConfig().ModTime.Before(info.ModTime()) {
 				break
 			}
 
@@ -323,7 +418,9 @@ finish_worker:
 			if err != nil {
 				mr.errorln("Failed to load config", err)
 				// don't reload the same file
+				mr.configMutex.Lock()
 				mr.config.ModTime = info.ModTime()
+				mr.configMutex.Unlock()
 				break
 			}
 
@@ -344,15 +441,14 @@ finish_worker:
 	}
 	mr.finished = true
 
-	// Pump signal to abort all builds
+	// Abort all builds, unless quit was requested
 	go func() {
 		for signaled == syscall.SIGQUIT {
 			log.Warningln("Requested quit, waiting for builds to finish")
 			signaled = <-mr.interruptSignal
 		}
-		for {
-			mr.abortBuilds <- signaled
-		}
+		mr.warningln("Aborting all builds:", signaled)
+		mr.abortBuilds()
 	}()
 
 	// Wait for workers to shutdown
@@ -368,7 +464,7 @@ func (mr *RunCommand) Stop(s service.Service) error {
 	mr.warningln("Requested service stop")
 	mr.interruptSignal <- os.Interrupt
 
-	signals := make(chan os.Signal)
+	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
 
 	select {
@@ -412,5 +508,9 @@ func (c *RunCommand) Execute(context *cli.Context) {
 func init() {
 	common.RegisterCommand2("run", "run multi runner service", &RunCommand{
 		ServiceName: defaultServiceName,
+		DockerCleanup: docker.CleanupOptions{
+			MaxAge:      defaultDockerCleanupMaxAge,
+			CacheMaxAge: defaultDockerCleanupCacheMaxAge,
+		},
 	})
 }
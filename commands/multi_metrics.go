@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+var concurrentDesc = prometheus.NewDesc(
+	"gitlab_runner_concurrent",
+	"Configured number of builds that can be run concurrently",
+	nil, nil,
+)
+
+var limitDesc = prometheus.NewDesc(
+	"gitlab_runner_limit",
+	"Configured number of builds that can be run concurrently by runner, 0 means no limit",
+	[]string{"runner"}, nil,
+)
+
+var runningBuildsDesc = prometheus.NewDesc(
+	"gitlab_runner_builds_running",
+	"Number of currently running builds",
+	[]string{"runner", "executor"}, nil,
+)
+
+var healthCheckFailuresDesc = prometheus.NewDesc(
+	"gitlab_runner_health_check_failures",
+	"Number of consecutive failed requests to coordinator",
+	[]string{"runner"}, nil,
+)
+
+var unhealthyDesc = prometheus.NewDesc(
+	"gitlab_runner_unhealthy",
+	"Whether runner is disabled, because of failed requests to coordinator",
+	[]string{"runner"}, nil,
+)
+
+// Describe implements prometheus.Collector
+func (mr *RunCommand) Describe(ch chan<- *prometheus.Desc) {
+	ch <- concurrentDesc
+	ch <- limitDesc
+	ch <- runningBuildsDesc
+	ch <- healthCheckFailuresDesc
+	ch <- unhealthyDesc
+}
+
+// Collect implements prometheus.Collector
+func (mr *RunCommand) Collect(ch chan<- prometheus.Metric) {
+	config := mr.getConfig()
+	if config == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(concurrentDesc, prometheus.GaugeValue, float64(config.Concurrent))
+
+	running := make(map[string]int)
+	mr.buildsLock.RLock()
+	for _, build := range mr.builds {
+		running[build.Runner.ShortDescription()]++
+	}
+	mr.buildsLock.RUnlock()
+
+	for _, runner := range config.Runners {
+		limit := 0
+		if runner.Limit != nil {
+			limit = *runner.Limit
+		}
+		ch <- prometheus.MustNewConstMetric(limitDesc, prometheus.GaugeValue,
+			float64(limit), runner.ShortDescription())
+		ch <- prometheus.MustNewConstMetric(runningBuildsDesc, prometheus.GaugeValue,
+			float64(running[runner.ShortDescription()]), runner.ShortDescription(), runner.Executor)
+
+		failures := mr.getHealthFailures(runner)
+		unhealthy := 0
+		if failures >= common.HealthyChecks {
+			unhealthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(healthCheckFailuresDesc, prometheus.GaugeValue,
+			float64(failures), runner.ShortDescription())
+		ch <- prometheus.MustNewConstMetric(unhealthyDesc, prometheus.GaugeValue,
+			float64(unhealthy), runner.ShortDescription())
+	}
+}
+
+func (mr *RunCommand) metricsHandler() (http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	err := registry.Register(mr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, collector := range []prometheus.Collector{
+		common.GetBuildRequests,
+		common.UpdateBuildFailures,
+		common.FinishedBuilds,
+	} {
+		err = registry.Register(collector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
+
+func (mr *RunCommand) serveMetrics() error {
+	if mr.MetricsListenAddress == "" {
+		return nil
+	}
+
+	handler, err := mr.metricsHandler()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", mr.MetricsListenAddress)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+
+	go func() {
+		err := http.Serve(listener, mux)
+		if err != nil {
+			mr.errorln("Metrics server failed:", err)
+		}
+	}()
+
+	mr.println("Metrics server listening on", listener.Addr())
+	return nil
+}
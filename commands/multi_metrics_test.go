@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	limit := 2
+	runner := &common.RunnerConfig{
+		RunnerCredentials: common.RunnerCredentials{
+			Token: "abcdef1234",
+		},
+		Limit:    &limit,
+		Executor: "shell",
+	}
+
+	mr := &RunCommand{}
+	mr.config = &common.Config{
+		Concurrent: 4,
+		Runners:    []*common.RunnerConfig{runner},
+	}
+	mr.builds = []*runningBuild{{Build: &common.Build{Runner: runner}}}
+	for i := 0; i < common.HealthyChecks; i++ {
+		mr.makeUnhealthy(runner)
+	}
+
+	handler, err := mr.metricsHandler()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, "gitlab_runner_concurrent 4\n")
+	assert.Contains(t, body, `gitlab_runner_limit{runner="abcdef12"} 2`)
+	assert.Contains(t, body, `gitlab_runner_builds_running{executor="shell",runner="abcdef12"} 1`)
+	assert.Contains(t, body, `gitlab_runner_health_check_failures{runner="abcdef12"} 3`)
+	assert.Contains(t, body, `gitlab_runner_unhealthy{runner="abcdef12"} 1`)
+}
@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+func TestBuildsAreAbortedSeparately(t *testing.T) {
+	runner := &common.RunnerConfig{}
+	mr := &RunCommand{}
+
+	first := &common.Build{Runner: runner}
+	second := &common.Build{Runner: runner}
+	firstCtx := mr.addBuild(first)
+	secondCtx := mr.addBuild(second)
+	assert.NotEqual(t, first.GlobalID, second.GlobalID)
+
+	mr.removeBuild(first)
+	assert.Error(t, firstCtx.Err())
+	assert.NoError(t, secondCtx.Err())
+	assert.Len(t, mr.builds, 1)
+
+	mr.abortBuilds()
+	assert.Error(t, secondCtx.Err())
+
+	// the builds added after abort are aborted as well
+	thirdCtx := mr.addBuild(&common.Build{Runner: runner})
+	assert.Error(t, thirdCtx.Err())
+}
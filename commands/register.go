@@ -7,7 +7,7 @@ import (
 	"strconv"
 	"strings"
 
-	log "github.com/Sirupsen/logrus"
+	log "github.com/sirupsen/logrus"
 	"github.com/codegangsta/cli"
 
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
@@ -104,7 +104,7 @@ func (s *RegisterCommand) askForDockerService(service string) bool {
 				continue
 			}
 		}
-		s.Docker.Services = append(s.Docker.Services, service+":"+result)
+		s.Docker.Services = append(s.Docker.Services, common.DockerService{Name: service + ":" + result})
 		return true
 	}
 }
@@ -126,6 +126,20 @@ func (s *RegisterCommand) askDocker() {
 	s.Docker.Volumes = append(s.Docker.Volumes, "/cache")
 }
 
+func (s *RegisterCommand) askKubernetes() {
+	if s.Kubernetes == nil {
+		s.Kubernetes = &common.KubernetesConfig{}
+	}
+	s.Kubernetes.Image = s.ask("kubernetes-image", "Please enter the default image for build pods (eg. ruby:2.1):")
+}
+
+func (s *RegisterCommand) askCustom() {
+	if s.Custom == nil {
+		s.Custom = &common.CustomConfig{}
+	}
+	s.Custom.RunExec = s.ask("custom-run-exec", "Please enter the program used to run builds (eg. /usr/local/bin/run-build):")
+}
+
 func (s *RegisterCommand) askParallels() {
 	s.Parallels.BaseName = s.ask("parallels-vm", "Please enter the Parallels VM (eg. my-vm):")
 }
@@ -220,19 +234,39 @@ func (c *RegisterCommand) Execute(context *cli.Context) {
 		c.askDocker()
 		c.SSH = nil
 		c.Parallels = nil
+		c.Kubernetes = nil
+		c.Custom = nil
 	case "docker-ssh":
 		c.askDocker()
 		c.askSSHLogin()
 		c.Parallels = nil
+		c.Kubernetes = nil
+		c.Custom = nil
 	case "ssh":
 		c.askSSHServer()
 		c.askSSHLogin()
 		c.Docker = nil
 		c.Parallels = nil
+		c.Kubernetes = nil
+		c.Custom = nil
 	case "parallels":
 		c.askParallels()
 		c.askSSHServer()
 		c.Docker = nil
+		c.Kubernetes = nil
+		c.Custom = nil
+	case "kubernetes":
+		c.askKubernetes()
+		c.SSH = nil
+		c.Docker = nil
+		c.Parallels = nil
+		c.Custom = nil
+	case "custom":
+		c.askCustom()
+		c.SSH = nil
+		c.Docker = nil
+		c.Parallels = nil
+		c.Kubernetes = nil
 	}
 
 	c.addRunner(&c.RunnerConfig)
@@ -249,10 +283,12 @@ func getHostname() string {
 func init() {
 	common.RegisterCommand2("register", "register a new runner", &RegisterCommand{
 		RunnerConfig: common.RunnerConfig{
-			Name:      getHostname(),
-			Parallels: &common.ParallelsConfig{},
-			SSH:       &ssh.Config{},
-			Docker:    &common.DockerConfig{},
+			Name:       getHostname(),
+			Parallels:  &common.ParallelsConfig{},
+			SSH:        &ssh.Config{},
+			Docker:     &common.DockerConfig{},
+			Kubernetes: &common.KubernetesConfig{},
+			Custom:     &common.CustomConfig{},
 		},
 	})
 }
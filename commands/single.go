@@ -1,11 +1,12 @@
 package commands
 
 import (
+	"context"
 	"github.com/codegangsta/cli"
 	"os"
 	"time"
 
-	log "github.com/Sirupsen/logrus"
+	log "github.com/sirupsen/logrus"
 
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
 	"os/signal"
@@ -28,13 +29,14 @@ func (r *RunSingleCommand) Execute(c *cli.Context) {
 	}
 
 	config := common.NewConfig()
-	signals := make(chan os.Signal)
+	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
 
 	log.Println("Starting runner for", r.URL, "with token", r.ShortDescription(), "...")
 
 	finished := false
-	abortSignal := make(chan os.Signal)
+	ctx, abortBuilds := context.WithCancel(context.Background())
+	defer abortBuilds()
 	doneSignal := make(chan int, 1)
 
 	go func() {
@@ -48,12 +50,7 @@ func (r *RunSingleCommand) Execute(c *cli.Context) {
 		}
 
 		log.Warningln("Requested exit:", interrupt)
-
-		go func() {
-			for {
-				abortSignal <- interrupt
-			}
-		}()
+		abortBuilds()
 
 		select {
 		case newSignal := <-signals:
@@ -70,7 +67,7 @@ func (r *RunSingleCommand) Execute(c *cli.Context) {
 			log.Println("Runner is not healthy!")
 			select {
 			case <-time.After(common.NotHealthyCheckInterval * time.Second):
-			case <-abortSignal:
+			case <-ctx.Done():
 			}
 			continue
 		}
@@ -78,7 +75,7 @@ func (r *RunSingleCommand) Execute(c *cli.Context) {
 		if buildData == nil {
 			select {
 			case <-time.After(common.CheckInterval * time.Second):
-			case <-abortSignal:
+			case <-ctx.Done():
 			}
 			continue
 		}
@@ -86,10 +83,9 @@ func (r *RunSingleCommand) Execute(c *cli.Context) {
 		newBuild := common.Build{
 			GetBuildResponse: *buildData,
 			Runner:           &r.RunnerConfig,
-			BuildAbort:       abortSignal,
 		}
 		newBuild.AssignID()
-		newBuild.Run(config)
+		newBuild.Run(ctx, config)
 	}
 
 	doneSignal <- 0
@@ -3,7 +3,7 @@ package commands
 import (
 	"github.com/codegangsta/cli"
 
-	log "github.com/Sirupsen/logrus"
+	log "github.com/sirupsen/logrus"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
 )
 
@@ -2,11 +2,13 @@ package common
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	log "github.com/sirupsen/logrus"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers"
+	"io"
 	"net/url"
-	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -24,15 +26,18 @@ const (
 
 type Build struct {
 	GetBuildResponse `yaml:",inline"`
-	BuildState       BuildState     `json:"build_state"`
-	BuildStarted     time.Time      `json:"build_started"`
-	BuildFinished    time.Time      `json:"build_finished"`
-	BuildDuration    time.Duration  `json:"build_duration"`
-	BuildAbort       chan os.Signal `json:"-" yaml:"-"`
-	RootDir          string         `json:"-" yaml:"-"`
-	BuildDir         string         `json:"-" yaml:"-"`
-	Hostname         string         `json:"-" yaml:"-"`
-	Runner           *RunnerConfig  `json:"runner"`
+	BuildState       BuildState    `json:"build_state"`
+	BuildStarted     time.Time     `json:"build_started"`
+	BuildFinished    time.Time     `json:"build_finished"`
+	BuildDuration    time.Duration `json:"build_duration"`
+	RootDir          string        `json:"-" yaml:"-"`
+	BuildDir         string        `json:"-" yaml:"-"`
+	Hostname         string        `json:"-" yaml:"-"`
+	Runner           *RunnerConfig `json:"runner"`
+
+	// LocalTrace receives the build log instead of coordinator when set,
+	// it's used to run builds without coordinator
+	LocalTrace io.Writer `json:"-" yaml:"-"`
 
 	// Unique ID for all running builds (globally)
 	GlobalID int `json:"global_id"`
@@ -45,6 +50,20 @@ type Build struct {
 
 	buildLog     bytes.Buffer
 	buildLogLock sync.RWMutex
+
+	// Part of build log already acknowledged by coordinator
+	sentTrace     int
+	fullTrace     bool
+	sentTraceLock sync.Mutex
+}
+
+// GetBuildTimeout returns the time after which the build is aborted
+func (b *Build) GetBuildTimeout() time.Duration {
+	timeout := b.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return time.Duration(timeout) * time.Second
 }
 
 func (b *Build) AssignID(otherBuilds ...*Build) {
@@ -156,12 +175,29 @@ func (b *Build) BuildLog() string {
 	return b.buildLog.String()
 }
 
+func (b *Build) buildLogFrom(offset int) string {
+	b.buildLogLock.RLock()
+	defer b.buildLogLock.RUnlock()
+
+	data := b.buildLog.Bytes()
+	if offset > len(data) {
+		offset = len(data)
+	}
+	return string(data[offset:])
+}
+
 func (b *Build) BuildLogLen() int {
 	b.buildLogLock.RLock()
 	defer b.buildLogLock.RUnlock()
 	return b.buildLog.Len()
 }
 
+func (b *Build) Write(data []byte) (int, error) {
+	b.buildLogLock.Lock()
+	defer b.buildLogLock.Unlock()
+	return b.buildLog.Write(data)
+}
+
 func (b *Build) WriteString(data string) (int, error) {
 	b.buildLogLock.Lock()
 	defer b.buildLogLock.Unlock()
@@ -174,20 +210,58 @@ func (b *Build) WriteRune(r rune) (int, error) {
 	return b.buildLog.WriteRune(r)
 }
 
-func (b *Build) SendBuildLog() {
-	var buildTrace string
+// UpdateTrace sends to coordinator the part of build log that wasn't yet acknowledged
+// and the build state. If coordinator doesn't support incremental trace updates,
+// the whole build log is sent every time. The builds with LocalTrace write it there instead.
+func (b *Build) UpdateTrace(state BuildState) UpdateState {
+	b.sentTraceLock.Lock()
+	defer b.sentTraceLock.Unlock()
+
+	if b.LocalTrace != nil {
+		trace := b.buildLogFrom(b.sentTrace)
+		b.sentTrace += len(trace)
+		io.WriteString(b.LocalTrace, trace)
+		return UpdateSucceeded
+	}
 
-	buildTrace = b.BuildLog()
-	for {
-		if UpdateBuild(*b.Runner, b.ID, b.BuildState, buildTrace) != UpdateFailed {
-			break
-		} else {
-			time.Sleep(UpdateRetryInterval * time.Second)
+	if !b.fullTrace {
+		result, offset := PatchTrace(*b.Runner, b.ID, b.sentTrace, b.buildLogFrom(b.sentTrace))
+		switch result {
+		case UpdateSucceeded:
+			b.sentTrace = offset
+			return UpdateBuild(*b.Runner, b.ID, state, "")
+
+		case UpdateRangeMismatch:
+			// resend the trace starting from what coordinator has
+			b.sentTrace = offset
+			return UpdateFailed
+
+		case UpdateNotSupported:
+			log.Warningln(b.Runner.ShortDescription(), b.ID, "Incremental trace is not supported, sending full trace")
+			b.fullTrace = true
+
+		default:
+			return result
 		}
 	}
+
+	return UpdateBuild(*b.Runner, b.ID, state, b.BuildLog())
+}
+
+func (b *Build) SendBuildLog() {
+	for b.UpdateTrace(b.BuildState) == UpdateFailed {
+		time.Sleep(UpdateRetryInterval * time.Second)
+	}
 }
 
-func (b *Build) Run(globalConfig *Config) error {
+// Run executes the build, it's aborted as soon as ctx is done
+func (b *Build) Run(ctx context.Context, globalConfig *Config) error {
+	defer observeFinishedBuild(b)
+
+	// the build timeout is the deadline of build context
+	ctx, cancel := context.WithTimeout(ctx, b.GetBuildTimeout())
+	defer cancel()
+
 	executor := NewExecutor(b.Runner.Executor)
 	if executor == nil {
 		b.WriteString("Executor not found: " + b.Runner.Executor)
@@ -195,17 +269,19 @@ func (b *Build) Run(globalConfig *Config) error {
 		return errors.New("executor not found")
 	}
 
-	err := executor.Prepare(globalConfig, b.Runner, b)
+	err := executor.Prepare(ctx, globalConfig, b.Runner, b)
 	if err == nil {
-		err = executor.Start()
+		err = executor.Start(ctx)
 	}
 	if err == nil {
-		err = executor.Wait()
-	}
-	executor.Finish(err)
-	if executor != nil {
-		executor.Cleanup()
+		err = executor.Wait(ctx)
 	}
+	executor.Finish(ctx, err)
+
+	// the build context can be already done, but the resources still have to be released
+	cleanupCtx, cancel := context.WithTimeout(context.Background(), CleanupTimeout)
+	defer cancel()
+	executor.Cleanup(cleanupCtx)
 	return err
 }
 
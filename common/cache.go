@@ -0,0 +1,48 @@
+package common
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CacheBackend stores the cache archives. The archives are transferred
+// by the runner commands executed in the build environment, so the backend
+// only returns the locations that can be accessed from there.
+type CacheBackend interface {
+	// GetDownloadURL returns the URL or the local path of cache archive,
+	// that is valid for the specified time
+	GetDownloadURL(objectName string, expires time.Duration) (string, error)
+
+	// GetUploadURL returns the URL or the local path for storing cache archive
+	GetUploadURL(objectName string, expires time.Duration) (string, error)
+}
+
+type CacheBackendFactory func(config *CacheConfig) (CacheBackend, error)
+
+var cacheBackends map[string]CacheBackendFactory
+
+func RegisterCacheBackend(name string, factory CacheBackendFactory) {
+	log.Debugln("Registering", name, "cache backend...")
+
+	if cacheBackends == nil {
+		cacheBackends = make(map[string]CacheBackendFactory)
+	}
+	if _, ok := cacheBackends[name]; ok {
+		panic("Cache backend already exist: " + name)
+	}
+	cacheBackends[name] = factory
+}
+
+func NewCacheBackend(config *CacheConfig) (CacheBackend, error) {
+	if config == nil || config.Type == "" {
+		return nil, nil
+	}
+
+	factory, ok := cacheBackends[config.Type]
+	if !ok {
+		return nil, fmt.Errorf("cache backend %s not found", config.Type)
+	}
+	return factory(config)
+}
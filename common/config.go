@@ -3,13 +3,14 @@ package common
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"io/ioutil"
 	"os"
 	"time"
 
 	"fmt"
 	"github.com/BurntSushi/toml"
-	log "github.com/Sirupsen/logrus"
+	log "github.com/sirupsen/logrus"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers/docker"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers/ssh"
@@ -18,18 +19,153 @@ import (
 
 type DockerConfig struct {
 	docker_helpers.DockerCredentials
-	Hostname               *string  `toml:"hostname" json:"hostname" long:"hostname" env:"DOCKER_HOSTNAME" description:"Custom container hostname"`
-	Image                  string   `toml:"image" json:"image" long:"image" env:"DOCKER_IMAGE" description:"Docker image to be used"`
-	Privileged             bool     `toml:"privileged" json:"privileged" long:"privileged" env:"DOCKER_PRIVILEGED" description:"Give extended privileges to container"`
-	DisableCache           *bool    `toml:"disable_cache" json:"disable_cache" long:"disable-cache" env:"DOCKER_DISABLE_CACHE" description:"Disable all container caching"`
-	Volumes                []string `toml:"volumes" json:"volumes" long:"volumes" env:"DOCKER_VOLUMES" description:"Bind mount a volumes"`
-	CacheDir               *string  `toml:"cache_dir" json:"cache_dir" long:"cache-dir" env:"DOCKER_CACHE_DIR" description:"Directory where to store caches"`
-	ExtraHosts             []string `toml:"extra_hosts" json:"extra_hosts" long:"extra-hosts" env:"DOCKER_EXTRA_HOSTS" description:"Add a custom host-to-IP mapping"`
-	Links                  []string `toml:"links" json:"links" long:"links" env:"DOCKER_LINKS" description:"Add link to another container"`
-	Services               []string `toml:"services" json:"services" long:"services" env:"DOCKER_SERVICES" description:"Add service that is started with container"`
-	WaitForServicesTimeout *int     `toml:"wait_for_services_timeout" json:"wait_for_services_timeout" long:"wait-for-services-timeout" env:"DOCKER_WAIT_FOR_SERVICES_TIMEOUT" description:"How long to wait for service startup"`
-	AllowedImages          []string `toml:"allowed_images" json:"allowed_images" long:"allowed-images" env:"DOCKER_ALLOWED_IMAGES" description:"Whitelist allowed images"`
-	AllowedServices        []string `toml:"allowed_services" json:"allowed_services" long:"allowed-services" env:"DOCKER_ALLOWED_SERVICES" description:"Whitelist allowed services"`
+	Hostname                 *string           `toml:"hostname" json:"hostname" long:"hostname" env:"DOCKER_HOSTNAME" description:"Custom container hostname"`
+	Image                    string            `toml:"image" json:"image" long:"image" env:"DOCKER_IMAGE" description:"Docker image to be used"`
+	Privileged               bool              `toml:"privileged" json:"privileged" long:"privileged" env:"DOCKER_PRIVILEGED" description:"Give extended privileges to container"`
+	DisableCache             *bool             `toml:"disable_cache" json:"disable_cache" long:"disable-cache" env:"DOCKER_DISABLE_CACHE" description:"Disable all container caching"`
+	Volumes                  []string          `toml:"volumes" json:"volumes" long:"volumes" env:"DOCKER_VOLUMES" description:"Bind mount a volumes"`
+	CacheDir                 *string           `toml:"cache_dir" json:"cache_dir" long:"cache-dir" env:"DOCKER_CACHE_DIR" description:"Directory where to store caches"`
+	ExtraHosts               []string          `toml:"extra_hosts" json:"extra_hosts" long:"extra-hosts" env:"DOCKER_EXTRA_HOSTS" description:"Add a custom host-to-IP mapping"`
+	Links                    []string          `toml:"links" json:"links" long:"links" env:"DOCKER_LINKS" description:"Add link to another container"`
+	Services                 []DockerService   `toml:"services" json:"services" long:"services" env:"DOCKER_SERVICES" description:"Add service that is started with container"`
+	WaitForServicesTimeout   *int              `toml:"wait_for_services_timeout" json:"wait_for_services_timeout" long:"wait-for-services-timeout" env:"DOCKER_WAIT_FOR_SERVICES_TIMEOUT" description:"How long to wait for service startup"`
+	AllowedImages            []string          `toml:"allowed_images" json:"allowed_images" long:"allowed-images" env:"DOCKER_ALLOWED_IMAGES" description:"Whitelist allowed images"`
+	AllowedServices          []string          `toml:"allowed_services" json:"allowed_services" long:"allowed-services" env:"DOCKER_ALLOWED_SERVICES" description:"Whitelist allowed services"`
+	PullPolicy               string            `toml:"pull_policy" json:"pull_policy" long:"pull-policy" env:"DOCKER_PULL_POLICY" description:"Image pull policy: always, if-not-present or never"`
+	AllowedPullPolicies      []string          `toml:"allowed_pull_policies" json:"allowed_pull_policies" long:"allowed-pull-policies" env:"DOCKER_ALLOWED_PULL_POLICIES" description:"Whitelist pull policies that can be specified in .gitlab-ci.yml"`
+	HelperImage              string            `toml:"helper_image" json:"helper_image" long:"helper-image" env:"DOCKER_HELPER_IMAGE" description:"Image used to fetch sources of the build"`
+	ImageTTL                 *int              `toml:"image_ttl" json:"image_ttl" long:"image-ttl" env:"DOCKER_IMAGE_TTL" description:"How long (in seconds) pulled image is considered up to date when pull policy is not specified"`
+	ImageCacheFile           *string           `toml:"image_cache_file" json:"image_cache_file" long:"image-cache-file" env:"DOCKER_IMAGE_CACHE_FILE" description:"File where the information about pulled images is stored"`
+	Memory                   string            `toml:"memory" json:"memory" long:"memory" env:"DOCKER_MEMORY" description:"Memory limit of build container (eg. 512m or 1g)"`
+	MemorySwap               string            `toml:"memory_swap" json:"memory_swap" long:"memory-swap" env:"DOCKER_MEMORY_SWAP" description:"Total memory and swap limit of build container"`
+	CPUs                     string            `toml:"cpus" json:"cpus" long:"cpus" env:"DOCKER_CPUS" description:"Number of CPUs available to build container (eg. 1.5)"`
+	CPUSetCPUs               string            `toml:"cpuset_cpus" json:"cpuset_cpus" long:"cpuset-cpus" env:"DOCKER_CPUSET_CPUS" description:"CPUs in which build container is allowed to run (eg. 0-3)"`
+	PidsLimit                int64             `toml:"pids_limit" json:"pids_limit" long:"pids-limit" env:"DOCKER_PIDS_LIMIT" description:"Maximum number of processes in build container"`
+	ShmSize                  string            `toml:"shm_size" json:"shm_size" long:"shm-size" env:"DOCKER_SHM_SIZE" description:"Size of /dev/shm of build container"`
+	ServiceMemory            string            `toml:"service_memory" json:"service_memory" long:"service-memory" env:"DOCKER_SERVICE_MEMORY" description:"Memory limit of service containers"`
+	ServiceMemorySwap        string            `toml:"service_memory_swap" json:"service_memory_swap" long:"service-memory-swap" env:"DOCKER_SERVICE_MEMORY_SWAP" description:"Total memory and swap limit of service containers"`
+	ServiceCPUs              string            `toml:"service_cpus" json:"service_cpus" long:"service-cpus" env:"DOCKER_SERVICE_CPUS" description:"Number of CPUs available to service containers"`
+	ServiceCPUSetCPUs        string            `toml:"service_cpuset_cpus" json:"service_cpuset_cpus" long:"service-cpuset-cpus" env:"DOCKER_SERVICE_CPUSET_CPUS" description:"CPUs in which service containers are allowed to run"`
+	ServicePidsLimit         int64             `toml:"service_pids_limit" json:"service_pids_limit" long:"service-pids-limit" env:"DOCKER_SERVICE_PIDS_LIMIT" description:"Maximum number of processes in service containers"`
+	ServiceShmSize           string            `toml:"service_shm_size" json:"service_shm_size" long:"service-shm-size" env:"DOCKER_SERVICE_SHM_SIZE" description:"Size of /dev/shm of service containers"`
+	CapAdd                   []string          `toml:"cap_add" json:"cap_add" long:"cap-add" env:"DOCKER_CAP_ADD" description:"Add Linux capabilities"`
+	CapDrop                  []string          `toml:"cap_drop" json:"cap_drop" long:"cap-drop" env:"DOCKER_CAP_DROP" description:"Drop Linux capabilities"`
+	SecurityOpt              []string          `toml:"security_opt" json:"security_opt" long:"security-opt" env:"DOCKER_SECURITY_OPT" description:"Security options (eg. seccomp or apparmor profiles)"`
+	Devices                  []string          `toml:"devices" json:"devices" long:"devices" env:"DOCKER_DEVICES" description:"Add host devices to containers"`
+	Tmpfs                    map[string]string `toml:"tmpfs" json:"tmpfs" long:"tmpfs" description:"Mount tmpfs directories with the given options"`
+	Sysctls                  map[string]string `toml:"sysctls" json:"sysctls" long:"sysctls" description:"Namespaced kernel parameters to set in containers"`
+	UsernsMode               string            `toml:"userns_mode" json:"userns_mode" long:"userns-mode" env:"DOCKER_USERNS_MODE" description:"User namespace mode of containers"`
+	ReadOnly                 bool              `toml:"read_only" json:"read_only" long:"read-only" env:"DOCKER_READ_ONLY" description:"Mount the root filesystem of containers as read only"`
+	AllowedCapAdd            []string          `toml:"allowed_cap_add" json:"allowed_cap_add" long:"allowed-cap-add" env:"DOCKER_ALLOWED_CAP_ADD" description:"Whitelist capabilities that can be added in .gitlab-ci.yml"`
+	AllowedSecurityOpt       []string          `toml:"allowed_security_opt" json:"allowed_security_opt" long:"allowed-security-opt" env:"DOCKER_ALLOWED_SECURITY_OPT" description:"Whitelist security options that can be specified in .gitlab-ci.yml"`
+	AllowedDevices           []string          `toml:"allowed_devices" json:"allowed_devices" long:"allowed-devices" env:"DOCKER_ALLOWED_DEVICES" description:"Whitelist devices that can be specified in .gitlab-ci.yml"`
+	AllowedTmpfs             []string          `toml:"allowed_tmpfs" json:"allowed_tmpfs" long:"allowed-tmpfs" env:"DOCKER_ALLOWED_TMPFS" description:"Whitelist tmpfs paths that can be specified in .gitlab-ci.yml"`
+	AllowedSysctls           []string          `toml:"allowed_sysctls" json:"allowed_sysctls" long:"allowed-sysctls" env:"DOCKER_ALLOWED_SYSCTLS" description:"Whitelist kernel parameters that can be specified in .gitlab-ci.yml"`
+	AllowedUsernsModes       []string          `toml:"allowed_userns_modes" json:"allowed_userns_modes" long:"allowed-userns-modes" env:"DOCKER_ALLOWED_USERNS_MODES" description:"Whitelist user namespace modes that can be specified in .gitlab-ci.yml"`
+	AllowedCredentialHelpers []string          `toml:"allowed_credential_helpers" json:"allowed_credential_helpers" long:"allowed-credential-helpers" env:"DOCKER_ALLOWED_CREDENTIAL_HELPERS" description:"Whitelist credential helpers that can be used by DOCKER_AUTH_CONFIG"`
+}
+
+type DockerService struct {
+	Name       string            `toml:"name" json:"name"`
+	Alias      string            `toml:"alias" json:"alias"`
+	Entrypoint []string          `toml:"entrypoint" json:"entrypoint"`
+	Command    []string          `toml:"command" json:"command"`
+	Variables  map[string]string `toml:"variables" json:"variables"`
+}
+
+func toString(value interface{}, field string) (string, error) {
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid service %s: %v", field, value)
+	}
+	return text, nil
+}
+
+func toStringSlice(value interface{}, field string) ([]string, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid service %s: %v", field, value)
+	}
+
+	var result []string
+	for _, item := range items {
+		text, err := toString(item, field)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, text)
+	}
+	return result, nil
+}
+
+// NewDockerService creates service from its image name
+// or from the service definition with name, alias, entrypoint, command and variables
+func NewDockerService(value interface{}) (DockerService, error) {
+	var service DockerService
+	err := service.UnmarshalTOML(value)
+	return service, err
+}
+
+func (s *DockerService) UnmarshalTOML(data interface{}) (err error) {
+	switch data := data.(type) {
+	case string:
+		*s = DockerService{Name: data}
+
+	case map[string]interface{}:
+		*s = DockerService{}
+		for key, value := range data {
+			switch key {
+			case "name":
+				s.Name, err = toString(value, key)
+
+			case "alias":
+				s.Alias, err = toString(value, key)
+
+			case "entrypoint":
+				s.Entrypoint, err = toStringSlice(value, key)
+
+			case "command":
+				s.Command, err = toStringSlice(value, key)
+
+			case "variables":
+				variables, ok := value.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("invalid service %s: %v", key, value)
+				}
+				s.Variables = make(map[string]string)
+				for name, variable := range variables {
+					s.Variables[name] = fmt.Sprint(variable)
+				}
+
+			default:
+				return fmt.Errorf("unknown service option: %s", key)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("invalid service: %v", data)
+	}
+
+	if s.Name == "" {
+		return errors.New("missing service name")
+	}
+	return nil
+}
+
+func (s *DockerService) UnmarshalFlag(value string) error {
+	*s = DockerService{Name: value}
+	return nil
+}
+
+func (s DockerService) String() string {
+	if s.Alias != "" {
+		return s.Name + " as " + s.Alias
+	}
+	return s.Name
 }
 
 type ParallelsConfig struct {
@@ -38,27 +174,81 @@ type ParallelsConfig struct {
 	DisableSnapshots *bool   `toml:"disable_snapshots" json:"disable_snapshots" long:"disable-snapshots" env:"PARALLELS_DISABLE_SNAPSHOTS" description:"Disable snapshoting to speedup VM creation"`
 }
 
+type KubernetesConfig struct {
+	Host                 string            `toml:"host" json:"host" long:"host" env:"KUBERNETES_HOST" description:"Kubernetes API server URL, if not specified in-cluster configuration is used"`
+	CertFile             string            `toml:"cert_file" json:"cert_file" long:"cert-file" env:"KUBERNETES_CERT_FILE" description:"Client certificate used to authenticate to Kubernetes"`
+	KeyFile              string            `toml:"key_file" json:"key_file" long:"key-file" env:"KUBERNETES_KEY_FILE" description:"Client key used to authenticate to Kubernetes"`
+	CAFile               string            `toml:"ca_file" json:"ca_file" long:"ca-file" env:"KUBERNETES_CA_FILE" description:"CA certificate used to verify Kubernetes API server"`
+	BearerToken          string            `toml:"bearer_token" json:"bearer_token" long:"bearer-token" env:"KUBERNETES_BEARER_TOKEN" description:"Token used to authenticate to Kubernetes"`
+	Image                string            `toml:"image" json:"image" long:"image" env:"KUBERNETES_IMAGE" description:"Default image to be used for builds"`
+	Namespace            string            `toml:"namespace" json:"namespace" long:"namespace" env:"KUBERNETES_NAMESPACE" description:"Namespace in which build pods are created"`
+	Privileged           bool              `toml:"privileged" json:"privileged" long:"privileged" env:"KUBERNETES_PRIVILEGED" description:"Run build containers in privileged mode"`
+	CPURequest           string            `toml:"cpu_request" json:"cpu_request" long:"cpu-request" env:"KUBERNETES_CPU_REQUEST" description:"CPU requested by build container"`
+	MemoryRequest        string            `toml:"memory_request" json:"memory_request" long:"memory-request" env:"KUBERNETES_MEMORY_REQUEST" description:"Memory requested by build container"`
+	CPULimit             string            `toml:"cpu_limit" json:"cpu_limit" long:"cpu-limit" env:"KUBERNETES_CPU_LIMIT" description:"CPU limit of build container"`
+	MemoryLimit          string            `toml:"memory_limit" json:"memory_limit" long:"memory-limit" env:"KUBERNETES_MEMORY_LIMIT" description:"Memory limit of build container"`
+	ServiceCPURequest    string            `toml:"service_cpu_request" json:"service_cpu_request" long:"service-cpu-request" env:"KUBERNETES_SERVICE_CPU_REQUEST" description:"CPU requested by service containers"`
+	ServiceMemoryRequest string            `toml:"service_memory_request" json:"service_memory_request" long:"service-memory-request" env:"KUBERNETES_SERVICE_MEMORY_REQUEST" description:"Memory requested by service containers"`
+	ServiceCPULimit      string            `toml:"service_cpu_limit" json:"service_cpu_limit" long:"service-cpu-limit" env:"KUBERNETES_SERVICE_CPU_LIMIT" description:"CPU limit of service containers"`
+	ServiceMemoryLimit   string            `toml:"service_memory_limit" json:"service_memory_limit" long:"service-memory-limit" env:"KUBERNETES_SERVICE_MEMORY_LIMIT" description:"Memory limit of service containers"`
+	NodeSelector         map[string]string `toml:"node_selector" json:"node_selector" long:"node-selector" description:"Labels of nodes on which build pods can be scheduled"`
+	ServiceAccount       string            `toml:"service_account" json:"service_account" long:"service-account" env:"KUBERNETES_SERVICE_ACCOUNT" description:"Service account used by build pods"`
+	Services             []string          `toml:"services" json:"services" long:"services" env:"KUBERNETES_SERVICES" description:"Add service that is started with build pod"`
+	AllowedImages        []string          `toml:"allowed_images" json:"allowed_images" long:"allowed-images" env:"KUBERNETES_ALLOWED_IMAGES" description:"Whitelist allowed images"`
+	AllowedServices      []string          `toml:"allowed_services" json:"allowed_services" long:"allowed-services" env:"KUBERNETES_ALLOWED_SERVICES" description:"Whitelist allowed services"`
+	PollInterval         int               `toml:"poll_interval" json:"poll_interval" long:"poll-interval" env:"KUBERNETES_POLL_INTERVAL" description:"How often (in seconds) to check the status of build pod"`
+	PollTimeout          int               `toml:"poll_timeout" json:"poll_timeout" long:"poll-timeout" env:"KUBERNETES_POLL_TIMEOUT" description:"How long (in seconds) to wait for build pod to start"`
+}
+
+type CustomConfig struct {
+	PrepareExec          string   `toml:"prepare_exec" json:"prepare_exec" long:"prepare-exec" env:"CUSTOM_PREPARE_EXEC" description:"Program executed to prepare build environment"`
+	PrepareArgs          []string `toml:"prepare_args" json:"prepare_args" long:"prepare-args" env:"CUSTOM_PREPARE_ARGS" description:"Arguments for the prepare program"`
+	RunExec              string   `toml:"run_exec" json:"run_exec" long:"run-exec" env:"CUSTOM_RUN_EXEC" description:"Program executed to run build script"`
+	RunArgs              []string `toml:"run_args" json:"run_args" long:"run-args" env:"CUSTOM_RUN_ARGS" description:"Arguments for the run program"`
+	CleanupExec          string   `toml:"cleanup_exec" json:"cleanup_exec" long:"cleanup-exec" env:"CUSTOM_CLEANUP_EXEC" description:"Program executed to cleanup build environment"`
+	CleanupArgs          []string `toml:"cleanup_args" json:"cleanup_args" long:"cleanup-args" env:"CUSTOM_CLEANUP_ARGS" description:"Arguments for the cleanup program"`
+	BuildFailureExitCode *int     `toml:"build_failure_exit_code" json:"build_failure_exit_code" long:"build-failure-exit-code" env:"CUSTOM_BUILD_FAILURE_EXIT_CODE" description:"Exit code of run program that marks failure of build script, other exit codes are treated as system failure"`
+}
+
+type CacheConfig struct {
+	Type           string `toml:"type" json:"type" long:"type" env:"CACHE_TYPE" description:"Select caching method: local or s3"`
+	Path           string `toml:"path" json:"path" long:"path" env:"CACHE_PATH" description:"Directory of local cache, or prefix of S3 objects"`
+	ServerAddress  string `toml:"server_address" json:"server_address" long:"s3-server-address" env:"CACHE_S3_SERVER_ADDRESS" description:"A host:port of S3 compatible server"`
+	AccessKey      string `toml:"access_key" json:"access_key" long:"s3-access-key" env:"CACHE_S3_ACCESS_KEY" description:"S3 Access Key"`
+	SecretKey      string `toml:"secret_key" json:"secret_key" long:"s3-secret-key" env:"CACHE_S3_SECRET_KEY" description:"S3 Secret Key"`
+	BucketName     string `toml:"bucket_name" json:"bucket_name" long:"s3-bucket-name" env:"CACHE_S3_BUCKET_NAME" description:"Name of the bucket where cache will be stored"`
+	BucketLocation string `toml:"bucket_location" json:"bucket_location" long:"s3-bucket-location" env:"CACHE_S3_BUCKET_LOCATION" description:"Region of the bucket, us-east-1 by default"`
+	Insecure       bool   `toml:"insecure" json:"insecure" long:"s3-insecure" env:"CACHE_S3_INSECURE" description:"Use plain HTTP to connect to S3 server"`
+}
+
 type RunnerCredentials struct {
-	URL            string  `toml:"url" json:"url" short:"u" long:"url" env:"CI_SERVER_URL" required:"true" description:"Runner URL"`
-	Token          string  `toml:"token" json:"token" short:"t" long:"token" env:"CI_SERVER_TOKEN" required:"true" description:"Runner token"`
+	URL   string `toml:"url" json:"url" short:"u" long:"url" env:"CI_SERVER_URL" required:"true" description:"Runner URL"`
+	Token string `toml:"token" json:"token" short:"t" long:"token" env:"CI_SERVER_TOKEN" required:"true" description:"Runner token"`
 }
 
 type RunnerConfig struct {
 	RunnerCredentials
-	Name           string  `toml:"name" json:"name" long:"name" env:"RUNNER_NAME" description:"Runner name"`
-	Limit          *int    `toml:"limit" json:"limit" long:"limit" env:"RUNNER_LIMIT" description:"Maximum number of builds processed by this runner"`
-	Executor       string  `toml:"executor" json:"executor" long:"executor" env:"RUNNER_EXECUTOR" required:"true" description:"Select executor, eg. shell, docker, etc."`
-	BuildsDir      *string `toml:"builds_dir" json:"builds_dir" long:"builds-dir" env:"RUNNER_BUILDS_DIR" description:"Directory where builds are stored"`
+	Name      string  `toml:"name" json:"name" long:"name" env:"RUNNER_NAME" description:"Runner name"`
+	Limit     *int    `toml:"limit" json:"limit" long:"limit" env:"RUNNER_LIMIT" description:"Maximum number of builds processed by this runner"`
+	Executor  string  `toml:"executor" json:"executor" long:"executor" env:"RUNNER_EXECUTOR" required:"true" description:"Select executor, eg. shell, docker, etc."`
+	BuildsDir *string `toml:"builds_dir" json:"builds_dir" long:"builds-dir" env:"RUNNER_BUILDS_DIR" description:"Directory where builds are stored"`
+
+	Environment []string `toml:"environment" json:"environment" long:"env" env:"RUNNER_ENV" description:"Custom environment variables injected to build environment"`
 
-	Environment    []string `toml:"environment" json:"environment" long:"env" env:"RUNNER_ENV" description:"Custom environment variables injected to build environment"`
+	Shell            *string `toml:"shell" json:"shell" long:"shell" env:"RUNNER_SHELL" description:"Select bash, cmd or powershell"`
+	DisableVerbose   *bool   `toml:"disable_verbose" json:"disable_verbose"`
+	OutputLimit      *int    `toml:"output_limit" long:"ouput-limit" env:"RUNNER_OUTPUT_LIMIT" description:"Maximum build trace size"`
+	MaxArtifactsSize *int    `toml:"max_artifacts_size" json:"max_artifacts_size" long:"max-artifacts-size" env:"RUNNER_MAX_ARTIFACTS_SIZE" description:"Maximum size of uploaded artifacts archive in megabytes"`
+	GitStrategy      *string `toml:"git_strategy" json:"git_strategy" long:"git-strategy" env:"RUNNER_GIT_STRATEGY" description:"Default strategy of fetching sources: clone, fetch or none"`
+	GitDepth         *int    `toml:"git_depth" json:"git_depth" long:"git-depth" env:"RUNNER_GIT_DEPTH" description:"Default number of commits fetched from repository, 0 fetches all"`
 
-	Shell          *string `toml:"shell" json:"shell" long:"shell" env:"RUNNER_SHELL" description:"Select bash, cmd or powershell"`
-	DisableVerbose *bool   `toml:"disable_verbose" json:"disable_verbose"`
-	OutputLimit    *int    `toml:"output_limit" long:"ouput-limit" env:"RUNNER_OUTPUT_LIMIT" description:"Maximum build trace size"`
+	SSH        *ssh.Config       `toml:"ssh" json:"ssh" group:"ssh executor" namespace:"ssh"`
+	Docker     *DockerConfig     `toml:"docker" json:"docker" group:"docker executor" namespace:"docker"`
+	Parallels  *ParallelsConfig  `toml:"parallels" json:"parallels" group:"parallels executor" namespace:"parallels"`
+	Kubernetes *KubernetesConfig `toml:"kubernetes" json:"kubernetes" group:"kubernetes executor" namespace:"kubernetes"`
+	Custom     *CustomConfig     `toml:"custom" json:"custom" group:"custom executor" namespace:"custom"`
 
-	SSH            *ssh.Config      `toml:"ssh" json:"ssh" group:"ssh executor" namespace:"ssh"`
-	Docker         *DockerConfig    `toml:"docker" json:"docker" group:"docker executor" namespace:"docker"`
-	Parallels      *ParallelsConfig `toml:"parallels" json:"parallels" group:"parallels executor" namespace:"parallels"`
+	Cache *CacheConfig `toml:"cache" json:"cache" group:"cache configuration" namespace:"cache"`
 }
 
 type BaseConfig struct {
@@ -69,8 +259,9 @@ type BaseConfig struct {
 
 type Config struct {
 	BaseConfig
-	ModTime time.Time `json:"-"`
-	Loaded  bool      `json:"-"`
+	ConfigFile string    `json:"-"`
+	ModTime    time.Time `json:"-"`
+	Loaded     bool      `json:"-"`
 }
 
 func (c *RunnerConfig) ShortDescription() string {
@@ -102,6 +293,7 @@ func (c *Config) StatConfig(configFile string) error {
 }
 
 func (c *Config) LoadConfig(configFile string) error {
+	c.ConfigFile = configFile
 	info, err := os.Stat(configFile)
 
 	// permission denied is soft error
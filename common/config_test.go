@@ -0,0 +1,73 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerServicesFromTOML(t *testing.T) {
+	var config DockerConfig
+	_, err := toml.Decode(`services = ["mysql:5.6", "redis"]`, &config)
+	assert.NoError(t, err)
+	assert.Equal(t, []DockerService{{Name: "mysql:5.6"}, {Name: "redis"}}, config.Services)
+
+	config = DockerConfig{}
+	_, err = toml.Decode(`
+[[services]]
+  name = "selenium/standalone-chrome"
+  alias = "chrome"
+  entrypoint = ["/opt/bin/entry_point.sh"]
+  command = ["--debug"]
+  [services.variables]
+    SCREEN_WIDTH = "1024"
+`, &config)
+	assert.NoError(t, err)
+	assert.Equal(t, []DockerService{
+		{
+			Name:       "selenium/standalone-chrome",
+			Alias:      "chrome",
+			Entrypoint: []string{"/opt/bin/entry_point.sh"},
+			Command:    []string{"--debug"},
+			Variables:  map[string]string{"SCREEN_WIDTH": "1024"},
+		},
+	}, config.Services)
+}
+
+func TestDockerServiceFromBuildOptions(t *testing.T) {
+	var options BuildOptions
+	err := json.Unmarshal([]byte(`{"services": [
+		"postgres:9.4",
+		{"name": "postgres:9.4", "alias": "replica", "variables": {"PORT": 5433}}
+	]}`), &options)
+	assert.NoError(t, err)
+
+	services := options["services"].([]interface{})
+	service, err := NewDockerService(services[0])
+	assert.NoError(t, err)
+	assert.Equal(t, DockerService{Name: "postgres:9.4"}, service)
+
+	service, err = NewDockerService(services[1])
+	assert.NoError(t, err)
+	assert.Equal(t, DockerService{
+		Name:      "postgres:9.4",
+		Alias:     "replica",
+		Variables: map[string]string{"PORT": "5433"},
+	}, service)
+}
+
+func TestInvalidDockerService(t *testing.T) {
+	_, err := NewDockerService(map[string]interface{}{"alias": "db"})
+	assert.Error(t, err)
+
+	_, err = NewDockerService(map[string]interface{}{"name": "mysql", "command": "mysqld"})
+	assert.Error(t, err)
+
+	_, err = NewDockerService(map[string]interface{}{"name": "mysql", "unknown": true})
+	assert.Error(t, err)
+
+	_, err = NewDockerService(10)
+	assert.Error(t, err)
+}
@@ -12,5 +12,8 @@ const HealthyChecks = 3
 const HealthCheckInterval = 3600
 const DefaultWaitForServicesTimeout = 30
 const ShutdownTimeout = 30
+const AfterScriptTimeout = 5 * time.Minute
+const CleanupTimeout = 5 * time.Minute
 const DefaultOutputLimit = 4096 // 4MB in kilobytes
 const ForceTraceSentInterval = 30 * time.Second
+const MinMaskedValueLength = 4
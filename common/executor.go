@@ -1,15 +1,30 @@
 package common
 
 import (
-	log "github.com/Sirupsen/logrus"
+	"context"
+
+	log "github.com/sirupsen/logrus"
 )
 
+// Executor runs a single build, the build is aborted when ctx passed
+// to Prepare, Start or Wait is done. Cleanup receives its own context,
+// so it's executed also for the aborted builds.
 type Executor interface {
-	Prepare(globalConfig *Config, config *RunnerConfig, build *Build) error
-	Start() error
-	Wait() error
-	Finish(err error)
-	Cleanup()
+	Prepare(ctx context.Context, globalConfig *Config, config *RunnerConfig, build *Build) error
+	Start(ctx context.Context) error
+	Wait(ctx context.Context) error
+	Finish(ctx context.Context, err error)
+	Cleanup(ctx context.Context)
+}
+
+// SystemError is returned by executor when build failed because
+// of the problem with the build environment, not the build script
+type SystemError struct {
+	Inner error
+}
+
+func (e *SystemError) Error() string {
+	return e.Inner.Error()
 }
 
 type ExecutorFactory struct {
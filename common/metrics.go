@@ -0,0 +1,47 @@
+package common
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var GetBuildRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gitlab_runner_get_build_requests_total",
+		Help: "Number of requests for new builds sent to coordinator, by response status code",
+	},
+	[]string{"runner", "status"},
+)
+
+var UpdateBuildFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gitlab_runner_update_build_failures_total",
+		Help: "Number of failed build updates sent to coordinator",
+	},
+	[]string{"runner"},
+)
+
+var FinishedBuilds = prometheus.NewSummaryVec(
+	prometheus.SummaryOpts{
+		Name: "gitlab_runner_build_duration_seconds",
+		Help: "Duration of finished builds, by final state",
+	},
+	[]string{"runner", "executor", "state"},
+)
+
+func statusLabel(statusCode int) string {
+	if statusCode < 0 {
+		return "error"
+	}
+	return strconv.Itoa(statusCode)
+}
+
+func observeFinishedBuild(b *Build) {
+	state := string(b.BuildState)
+	if state == "" {
+		state = string(Failed)
+	}
+
+	FinishedBuilds.WithLabelValues(b.Runner.ShortDescription(), b.Runner.Executor, state).Observe(b.BuildDuration.Seconds())
+}
@@ -4,9 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
-	log "github.com/Sirupsen/logrus"
+	log "github.com/sirupsen/logrus"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers"
 	"runtime"
 )
@@ -17,12 +23,42 @@ const (
 	UpdateSucceeded UpdateState = iota
 	UpdateAbort
 	UpdateFailed
+	UpdateRangeMismatch
+	UpdateNotSupported
 )
 
+type UploadState int
+
+const (
+	UploadSucceeded UploadState = iota
+	UploadTooLarge
+	UploadForbidden
+	UploadRejected
+	UploadFailed
+)
+
+type DownloadState int
+
+const (
+	DownloadSucceeded DownloadState = iota
+	DownloadForbidden
+	DownloadNotFound
+	DownloadFailed
+)
+
+// BuildCredentials are used by the commands executed in the build environment
+// to access the coordinator on behalf of the build
+type BuildCredentials struct {
+	ID    int    `long:"id" env:"CI_BUILD_ID" description:"The build ID"`
+	Token string `long:"token" env:"CI_BUILD_TOKEN" description:"The build token"`
+	URL   string `long:"url" env:"CI_SERVER_URL" description:"GitLab CI URL"`
+}
+
 type FeaturesInfo struct {
 	Variables bool `json:"variables"`
 	Image     bool `json:"image"`
 	Services  bool `json:"services"`
+	Trace     bool `json:"trace"`
 }
 
 type VersionInfo struct {
@@ -48,9 +84,100 @@ type BuildVariable struct {
 
 type BuildOptions map[string]interface{}
 
+// GetCommands returns commands of script option, like after_script,
+// that can be specified as string or list of strings
+func (o BuildOptions) GetCommands(name string) (string, bool) {
+	switch value := o[name].(type) {
+	case string:
+		return value, value != ""
+
+	case []interface{}:
+		var commands []string
+		for _, command := range value {
+			if command, ok := command.(string); ok {
+				commands = append(commands, command)
+			}
+		}
+		return strings.Join(commands, "\n"), len(commands) != 0
+	}
+	return "", false
+}
+
+func getPaths(value interface{}) (paths []string) {
+	values, _ := value.([]interface{})
+	for _, path := range values {
+		if path, ok := path.(string); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return
+}
+
+type BuildArtifacts struct {
+	Name      string
+	Paths     []string
+	Untracked bool
+	ExpireIn  string
+}
+
+// GetArtifacts returns the artifacts option, it's defined when there's anything to upload
+func (o BuildOptions) GetArtifacts() (*BuildArtifacts, bool) {
+	options, ok := o["artifacts"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	artifacts := &BuildArtifacts{}
+	artifacts.Name, _ = options["name"].(string)
+	artifacts.Untracked, _ = options["untracked"].(bool)
+	artifacts.ExpireIn, _ = options["expire_in"].(string)
+
+	artifacts.Paths = getPaths(options["paths"])
+
+	return artifacts, len(artifacts.Paths) != 0 || artifacts.Untracked
+}
+
+type BuildCache struct {
+	Key       string
+	Paths     []string
+	Untracked bool
+}
+
+// GetCache returns the cache option, it's defined when there's anything to cache
+func (o BuildOptions) GetCache() (*BuildCache, bool) {
+	options, ok := o["cache"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	cache := &BuildCache{}
+	cache.Key, _ = options["key"].(string)
+	cache.Untracked, _ = options["untracked"].(bool)
+
+	cache.Paths = getPaths(options["paths"])
+
+	return cache, len(cache.Paths) != 0 || cache.Untracked
+}
+
+type BuildArtifactsFile struct {
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256,omitempty"`
+}
+
+// BuildDependency is a build of previous stages which artifacts are used by the build
+type BuildDependency struct {
+	ID            int                 `json:"id"`
+	Name          string              `json:"name"`
+	Token         string              `json:"token"`
+	ArtifactsFile *BuildArtifactsFile `json:"artifacts_file,omitempty"`
+}
+
 type GetBuildResponse struct {
 	ID            int             `json:"id,omitempty"`
 	ProjectID     int             `json:"project_id,omitempty"`
+	Name          string          `json:"name,omitempty"`
+	Stage         string          `json:"stage,omitempty"`
 	Commands      string          `json:"commands,omitempty"`
 	RepoURL       string          `json:"repo_url,omitempty"`
 	Sha           string          `json:"sha,omitempty"`
@@ -60,6 +187,8 @@ type GetBuildResponse struct {
 	Timeout       int             `json:"timeout,omitempty"`
 	Variables     []BuildVariable `json:"variables"`
 	Options       BuildOptions    `json:"options"`
+
+	DependsOnBuilds []BuildDependency `json:"depends_on_builds,omitempty"`
 }
 
 type RegisterRunnerRequest struct {
@@ -158,6 +287,8 @@ func GetRunnerVersion(executor string) VersionInfo {
 		info.Features = *features
 	}
 
+	// incremental trace upload is handled by runner, not by executor
+	info.Features.Trace = true
 	return info
 }
 
@@ -169,6 +300,7 @@ func GetBuild(config RunnerConfig) (*GetBuildResponse, bool) {
 
 	var response GetBuildResponse
 	result, statusText := postJSON(getURL(config.URL, "builds/register.json"), 201, &request, &response)
+	GetBuildRequests.WithLabelValues(config.ShortDescription(), statusLabel(result)).Inc()
 
 	switch result {
 	case 201:
@@ -268,6 +400,189 @@ func UpdateBuild(config RunnerConfig, id int, state BuildState, trace string) Up
 		return UpdateAbort
 	default:
 		log.Warningln(config.ShortDescription(), id, "Submitting build to coordinator...", "failed", statusText)
+		UpdateBuildFailures.WithLabelValues(config.ShortDescription()).Inc()
 		return UpdateFailed
 	}
 }
+
+func parseTraceRange(contentRange string) (int, bool) {
+	// coordinator returns the range of trace that it already has: 0-<offset>
+	parts := strings.SplitN(contentRange, "-", 2)
+	if len(parts) != 2 {
+		return 0, false
+	}
+
+	offset, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || offset < 0 {
+		return 0, false
+	}
+	return offset, true
+}
+
+func PatchTrace(config RunnerConfig, id int, offset int, trace string) (UpdateState, int) {
+	if trace == "" {
+		return UpdateSucceeded, offset
+	}
+
+	req, err := http.NewRequest("PATCH", getURL(config.URL, "builds/%d/trace.txt?token=%v", id, config.Token), strings.NewReader(trace))
+	if err != nil {
+		log.Errorln(config.ShortDescription(), id, "Appending trace to coordinator...", "failed to create NewRequest:", err)
+		return UpdateFailed, offset
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Content-Range", fmt.Sprintf("%d-%d", offset, offset+len(trace)-1))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Warningln(config.ShortDescription(), id, "Appending trace to coordinator...", "failed", err)
+		return UpdateFailed, offset
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case 202:
+		log.Debugln(config.ShortDescription(), id, "Appending trace to coordinator...", "ok")
+		if remoteOffset, ok := parseTraceRange(res.Header.Get("Range")); ok {
+			return UpdateSucceeded, remoteOffset
+		}
+		return UpdateSucceeded, offset + len(trace)
+	case 416:
+		log.Warningln(config.ShortDescription(), id, "Appending trace to coordinator...", "range mismatch")
+		if remoteOffset, ok := parseTraceRange(res.Header.Get("Range")); ok {
+			return UpdateRangeMismatch, remoteOffset
+		}
+		return UpdateRangeMismatch, 0
+	case 403:
+		log.Errorln(config.ShortDescription(), id, "Appending trace to coordinator...", "forbidden")
+		return UpdateAbort, offset
+	case 404, 405, 501:
+		log.Warningln(config.ShortDescription(), id, "Appending trace to coordinator...", "not supported")
+		return UpdateNotSupported, offset
+	default:
+		log.Warningln(config.ShortDescription(), id, "Appending trace to coordinator...", "failed", res.Status)
+		return UpdateFailed, offset
+	}
+}
+
+func UploadArtifacts(config BuildCredentials, artifactsFile string, expireIn string) UploadState {
+	file, err := os.Open(artifactsFile)
+	if err != nil {
+		log.Errorln(config.ID, "Uploading artifacts to coordinator...", "failed to open file:", err)
+		return UploadFailed
+	}
+	defer file.Close()
+
+	// stream the file instead of reading it to memory, as artifacts can be large
+	pr, pw := io.Pipe()
+	mpw := multipart.NewWriter(pw)
+
+	go func() {
+		defer pw.Close()
+
+		if expireIn != "" {
+			err := mpw.WriteField("expire_in", expireIn)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+
+		part, err := mpw.CreateFormFile("file", filepath.Base(artifactsFile))
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		_, err = io.Copy(part, file)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(mpw.Close())
+	}()
+
+	req, err := http.NewRequest("POST", getURL(config.URL, "builds/%d/artifacts", config.ID), pr)
+	if err != nil {
+		pr.Close()
+		log.Errorln(config.ID, "Uploading artifacts to coordinator...", "failed to create NewRequest:", err)
+		return UploadFailed
+	}
+	req.Header.Set("Content-Type", mpw.FormDataContentType())
+	req.Header.Set("BUILD-TOKEN", config.Token)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		pr.Close()
+		log.Warningln(config.ID, "Uploading artifacts to coordinator...", "failed", err)
+		return UploadFailed
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case 201:
+		log.Println(config.ID, "Uploading artifacts to coordinator...", "ok")
+		return UploadSucceeded
+	case 403:
+		log.Errorln(config.ID, "Uploading artifacts to coordinator...", "forbidden")
+		return UploadForbidden
+	case 413:
+		log.Errorln(config.ID, "Uploading artifacts to coordinator...", "too large archive")
+		return UploadTooLarge
+	case 408, 429:
+		log.Warningln(config.ID, "Uploading artifacts to coordinator...", "failed", res.Status)
+		return UploadFailed
+	}
+
+	// the client errors will not be fixed by retrying
+	if res.StatusCode >= 400 && res.StatusCode < 500 {
+		log.Errorln(config.ID, "Uploading artifacts to coordinator...", "rejected", res.Status)
+		return UploadRejected
+	}
+	log.Warningln(config.ID, "Uploading artifacts to coordinator...", "failed", res.Status)
+	return UploadFailed
+}
+
+func DownloadArtifacts(config BuildCredentials, artifactsFile string) DownloadState {
+	req, err := http.NewRequest("GET", getURL(config.URL, "builds/%d/artifacts", config.ID), nil)
+	if err != nil {
+		log.Errorln(config.ID, "Downloading artifacts from coordinator...", "failed to create NewRequest:", err)
+		return DownloadFailed
+	}
+	req.Header.Set("BUILD-TOKEN", config.Token)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Warningln(config.ID, "Downloading artifacts from coordinator...", "failed", err)
+		return DownloadFailed
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case 200:
+	case 403:
+		log.Errorln(config.ID, "Downloading artifacts from coordinator...", "forbidden")
+		return DownloadForbidden
+	case 404:
+		log.Errorln(config.ID, "Downloading artifacts from coordinator...", "not found")
+		return DownloadNotFound
+	default:
+		log.Warningln(config.ID, "Downloading artifacts from coordinator...", "failed", res.Status)
+		return DownloadFailed
+	}
+
+	file, err := os.Create(artifactsFile)
+	if err != nil {
+		log.Errorln(config.ID, "Downloading artifacts from coordinator...", "failed to create file:", err)
+		return DownloadFailed
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, res.Body)
+	if err != nil {
+		log.Warningln(config.ID, "Downloading artifacts from coordinator...", "failed", err)
+		return DownloadFailed
+	}
+
+	log.Println(config.ID, "Downloading artifacts from coordinator...", "ok")
+	return DownloadSucceeded
+}
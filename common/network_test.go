@@ -0,0 +1,336 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceRange(t *testing.T) {
+	offset, ok := parseTraceRange("0-100")
+	assert.True(t, ok)
+	assert.Equal(t, 100, offset)
+
+	_, ok = parseTraceRange("")
+	assert.False(t, ok)
+
+	_, ok = parseTraceRange("0-abc")
+	assert.False(t, ok)
+}
+
+func TestPatchTrace(t *testing.T) {
+	var received string
+	var contentRange string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		assert.Equal(t, "/api/v1/builds/1/trace.txt", r.URL.Path)
+		assert.Equal(t, "token", r.URL.Query().Get("token"))
+
+		data, _ := ioutil.ReadAll(r.Body)
+		received = string(data)
+		contentRange = r.Header.Get("Content-Range")
+		w.WriteHeader(202)
+	}))
+	defer server.Close()
+
+	config := RunnerConfig{
+		RunnerCredentials: RunnerCredentials{
+			URL:   server.URL,
+			Token: "token",
+		},
+	}
+
+	state, offset := PatchTrace(config, 1, 10, "trace")
+	assert.Equal(t, UpdateSucceeded, state)
+	assert.Equal(t, 15, offset)
+	assert.Equal(t, "trace", received)
+	assert.Equal(t, "10-14", contentRange)
+}
+
+func TestPatchTraceRangeMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Range", "0-5")
+		w.WriteHeader(416)
+	}))
+	defer server.Close()
+
+	config := RunnerConfig{
+		RunnerCredentials: RunnerCredentials{
+			URL: server.URL,
+		},
+	}
+
+	state, offset := PatchTrace(config, 1, 10, "trace")
+	assert.Equal(t, UpdateRangeMismatch, state)
+	assert.Equal(t, 5, offset)
+}
+
+func TestUpdateTraceFallbackToFullTrace(t *testing.T) {
+	var fullTrace string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PATCH":
+			w.WriteHeader(404)
+		case "PUT":
+			var request UpdateBuildRequest
+			data, _ := ioutil.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(data, &request))
+			fullTrace = request.Trace
+			w.WriteHeader(200)
+		}
+	}))
+	defer server.Close()
+
+	build := &Build{
+		Runner: &RunnerConfig{
+			RunnerCredentials: RunnerCredentials{
+				URL: server.URL,
+			},
+		},
+	}
+	build.WriteString("build log")
+
+	assert.Equal(t, UpdateSucceeded, build.UpdateTrace(Running))
+	assert.Equal(t, "build log", fullTrace)
+	assert.True(t, build.fullTrace)
+}
+
+func TestUpdateTraceSendsOnlyNewData(t *testing.T) {
+	var patches []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PATCH":
+			data, _ := ioutil.ReadAll(r.Body)
+			patches = append(patches, string(data))
+			w.WriteHeader(202)
+		case "PUT":
+			var request UpdateBuildRequest
+			data, _ := ioutil.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(data, &request))
+			assert.Empty(t, request.Trace)
+			w.WriteHeader(200)
+		}
+	}))
+	defer server.Close()
+
+	build := &Build{
+		Runner: &RunnerConfig{
+			RunnerCredentials: RunnerCredentials{
+				URL: server.URL,
+			},
+		},
+	}
+
+	build.WriteString("first ")
+	assert.Equal(t, UpdateSucceeded, build.UpdateTrace(Running))
+	build.WriteString("second")
+	assert.Equal(t, UpdateSucceeded, build.UpdateTrace(Success))
+	assert.Equal(t, []string{"first ", "second"}, patches)
+}
+
+func TestUpdateTraceWritesToLocalTrace(t *testing.T) {
+	var trace bytes.Buffer
+
+	// the build without coordinator URL would fail to send the trace
+	build := &Build{
+		Runner:     &RunnerConfig{},
+		LocalTrace: &trace,
+	}
+
+	build.WriteString("first ")
+	assert.Equal(t, UpdateSucceeded, build.UpdateTrace(Running))
+	build.WriteString("second")
+	assert.Equal(t, UpdateSucceeded, build.UpdateTrace(Success))
+	assert.Equal(t, UpdateSucceeded, build.UpdateTrace(Success))
+	assert.Equal(t, "first second", trace.String())
+}
+
+func TestBuildOptionsGetCommands(t *testing.T) {
+	options := BuildOptions{
+		"string":  "echo 1",
+		"list":    []interface{}{"echo 1", "echo 2"},
+		"empty":   []interface{}{},
+		"invalid": 1,
+	}
+
+	commands, ok := options.GetCommands("string")
+	assert.True(t, ok)
+	assert.Equal(t, "echo 1", commands)
+
+	commands, ok = options.GetCommands("list")
+	assert.True(t, ok)
+	assert.Equal(t, "echo 1\necho 2", commands)
+
+	_, ok = options.GetCommands("empty")
+	assert.False(t, ok)
+
+	_, ok = options.GetCommands("invalid")
+	assert.False(t, ok)
+
+	_, ok = options.GetCommands("missing")
+	assert.False(t, ok)
+}
+
+func TestBuildOptionsGetArtifacts(t *testing.T) {
+	options := BuildOptions{
+		"artifacts": map[string]interface{}{
+			"name":      "release",
+			"paths":     []interface{}{"bin/", "", 1},
+			"expire_in": "1 week",
+		},
+	}
+
+	artifacts, ok := options.GetArtifacts()
+	assert.True(t, ok)
+	assert.Equal(t, "release", artifacts.Name)
+	assert.Equal(t, []string{"bin/"}, artifacts.Paths)
+	assert.False(t, artifacts.Untracked)
+	assert.Equal(t, "1 week", artifacts.ExpireIn)
+
+	options = BuildOptions{
+		"artifacts": map[string]interface{}{
+			"untracked": true,
+		},
+	}
+	artifacts, ok = options.GetArtifacts()
+	assert.True(t, ok)
+	assert.True(t, artifacts.Untracked)
+
+	options = BuildOptions{
+		"artifacts": map[string]interface{}{
+			"name": "nothing to upload",
+		},
+	}
+	_, ok = options.GetArtifacts()
+	assert.False(t, ok)
+
+	_, ok = BuildOptions{}.GetArtifacts()
+	assert.False(t, ok)
+}
+
+func TestBuildOptionsGetCache(t *testing.T) {
+	options := BuildOptions{
+		"cache": map[string]interface{}{
+			"key":   "$CI_BUILD_REF_NAME",
+			"paths": []interface{}{"vendor/"},
+		},
+	}
+
+	cache, ok := options.GetCache()
+	assert.True(t, ok)
+	assert.Equal(t, "$CI_BUILD_REF_NAME", cache.Key)
+	assert.Equal(t, []string{"vendor/"}, cache.Paths)
+
+	options = BuildOptions{
+		"cache": map[string]interface{}{
+			"key": "nothing to cache",
+		},
+	}
+	_, ok = options.GetCache()
+	assert.False(t, ok)
+}
+
+func uploadTestArtifacts(t *testing.T, statusCode int) (UploadState, *http.Request, string) {
+	var request *http.Request
+	var content string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		file, _, err := r.FormFile("file")
+		if err == nil {
+			data, _ := ioutil.ReadAll(file)
+			content = string(data)
+		}
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	tempFile, err := ioutil.TempFile("", "artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.WriteString("content")
+	tempFile.Close()
+
+	config := BuildCredentials{
+		ID:    1,
+		Token: "token",
+		URL:   server.URL,
+	}
+	return UploadArtifacts(config, tempFile.Name(), "1 day"), request, content
+}
+
+func TestUploadArtifacts(t *testing.T) {
+	state, request, content := uploadTestArtifacts(t, 201)
+	assert.Equal(t, UploadSucceeded, state)
+	assert.Equal(t, "POST", request.Method)
+	assert.Equal(t, "/api/v1/builds/1/artifacts", request.URL.Path)
+	assert.Equal(t, "token", request.Header.Get("BUILD-TOKEN"))
+	assert.Equal(t, "1 day", request.FormValue("expire_in"))
+	assert.Equal(t, "content", content)
+
+	state, _, _ = uploadTestArtifacts(t, 403)
+	assert.Equal(t, UploadForbidden, state)
+
+	state, _, _ = uploadTestArtifacts(t, 413)
+	assert.Equal(t, UploadTooLarge, state)
+
+	state, _, _ = uploadTestArtifacts(t, 400)
+	assert.Equal(t, UploadRejected, state)
+
+	for _, statusCode := range []int{408, 429, 500, 502} {
+		state, _, _ = uploadTestArtifacts(t, statusCode)
+		assert.Equal(t, UploadFailed, state, "%d", statusCode)
+	}
+}
+
+func TestDownloadArtifacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		switch r.URL.Path {
+		case "/api/v1/builds/1/artifacts":
+			if r.Header.Get("BUILD-TOKEN") != "token" {
+				w.WriteHeader(403)
+				return
+			}
+			w.WriteHeader(200)
+			w.Write([]byte("content"))
+		case "/api/v1/builds/2/artifacts":
+			w.WriteHeader(500)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	tempFile, err := ioutil.TempFile("", "artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempFile.Close()
+	defer os.Remove(tempFile.Name())
+
+	config := BuildCredentials{ID: 1, Token: "token", URL: server.URL}
+	assert.Equal(t, DownloadSucceeded, DownloadArtifacts(config, tempFile.Name()))
+	data, _ := ioutil.ReadFile(tempFile.Name())
+	assert.Equal(t, "content", string(data))
+
+	config.Token = "invalid"
+	assert.Equal(t, DownloadForbidden, DownloadArtifacts(config, tempFile.Name()))
+
+	config.ID = 2
+	assert.Equal(t, DownloadFailed, DownloadArtifacts(config, tempFile.Name()))
+
+	config.ID = 3
+	assert.Equal(t, DownloadNotFound, DownloadArtifacts(config, tempFile.Name()))
+}
@@ -2,11 +2,37 @@ package common
 
 import (
 	"fmt"
-	log "github.com/Sirupsen/logrus"
+	log "github.com/sirupsen/logrus"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers"
 	"strings"
 )
 
+type ShellScriptStage string
+
+const (
+	ShellPrepare     ShellScriptStage = "prepare"
+	ShellGetSources  ShellScriptStage = "get_sources"
+	ShellUserScript  ShellScriptStage = "user_script"
+	ShellAfterScript ShellScriptStage = "after_script"
+
+	ShellRestoreCache      ShellScriptStage = "restore_cache"
+	ShellDownloadArtifacts ShellScriptStage = "download_artifacts"
+	ShellArchiveCache      ShellScriptStage = "archive_cache"
+	ShellUploadArtifacts   ShellScriptStage = "upload_artifacts"
+)
+
+// ShellBuildStages are executed in order until one of them fails,
+// the ShellAfterScript is executed always at the end
+var ShellBuildStages = []ShellScriptStage{
+	ShellPrepare,
+	ShellGetSources,
+	ShellRestoreCache,
+	ShellDownloadArtifacts,
+	ShellUserScript,
+	ShellArchiveCache,
+	ShellUploadArtifacts,
+}
+
 type ShellScript struct {
 	Environment []string
 	Script      string
@@ -14,6 +40,10 @@ type ShellScript struct {
 	Arguments   []string
 	PassFile    bool
 	Extension   string
+
+	// Stages contains the parts of Script that can be executed separately,
+	// each of them is a complete script for Command
+	Stages map[ShellScriptStage]string
 }
 
 type ShellType int
@@ -43,6 +73,15 @@ func (s *ShellScript) GetScriptBytes() []byte {
 	return []byte(s.Script)
 }
 
+func (s *ShellScript) GetStageScript(stage ShellScriptStage) (string, bool) {
+	script, ok := s.Stages[stage]
+	return script, ok
+}
+
+func (s *ShellScript) GetStageScriptBytes(stage ShellScriptStage) []byte {
+	return []byte(s.Stages[stage])
+}
+
 func (s *ShellScript) String() string {
 	return helpers.ToYAML(s)
 }
@@ -53,6 +92,10 @@ type ShellScriptInfo struct {
 	Type        ShellType
 	User        *string
 	Environment []BuildVariable
+
+	// RunnerCommand is used to execute the runner helper commands,
+	// like artifacts-uploader, in the build environment
+	RunnerCommand string
 }
 
 type Shell interface {
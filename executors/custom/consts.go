@@ -0,0 +1,9 @@
+package custom
+
+const DefaultBuildFailureExitCode = 1
+
+const (
+	prepareStage = "prepare"
+	runStage     = "run"
+	cleanupStage = "cleanup"
+)
@@ -0,0 +1,214 @@
+package custom
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers"
+)
+
+type CustomExecutor struct {
+	executors.AbstractExecutor
+	cmd        *exec.Cmd
+	scriptDir  string
+	scriptFile string
+}
+
+func getExitCode(err error) (int, bool) {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus(), true
+		}
+	}
+	return -1, false
+}
+
+func (s *CustomExecutor) newCommand(stage, path string, args []string) *exec.Cmd {
+	cmd := exec.Command(path, args...)
+	helpers.SetProcessGroup(cmd)
+
+	// Pass build variables and location of build to the program
+	cmd.Env = os.Environ()
+	if s.ShellScript != nil {
+		cmd.Env = append(cmd.Env, s.ShellScript.Environment...)
+	}
+	cmd.Env = append(cmd.Env,
+		"RUNNER_STAGE="+stage,
+		"RUNNER_BUILD_DIR="+s.Build.FullProjectDir(),
+		"RUNNER_SCRIPT_FILE="+s.scriptFile,
+	)
+	return cmd
+}
+
+// runCommand executes the program,
+// the whole process group is killed when ctx is done
+func runCommand(ctx context.Context, cmd *exec.Cmd) error {
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+	return waitCommand(ctx, cmd)
+}
+
+func waitCommand(ctx context.Context, cmd *exec.Cmd) error {
+	waitCh := make(chan error, 1)
+	go func() {
+		waitCh <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-waitCh:
+		return err
+
+	case <-ctx.Done():
+		helpers.KillProcessGroup(cmd)
+		<-waitCh
+		return ctx.Err()
+	}
+}
+
+func (s *CustomExecutor) runFailure(err error) error {
+	exitCode, ok := getExitCode(err)
+	if !ok {
+		return &common.SystemError{Inner: err}
+	}
+
+	buildFailureExitCode := DefaultBuildFailureExitCode
+	if s.Config.Custom.BuildFailureExitCode != nil {
+		buildFailureExitCode = *s.Config.Custom.BuildFailureExitCode
+	}
+
+	if exitCode == buildFailureExitCode {
+		return fmt.Errorf("exit code %d", exitCode)
+	}
+	return &common.SystemError{Inner: fmt.Errorf("%s program failed with exit code %d", runStage, exitCode)}
+}
+
+func (s *CustomExecutor) writeScript() error {
+	scriptDir, err := ioutil.TempDir("", "build_script")
+	if err != nil {
+		return err
+	}
+	s.scriptDir = scriptDir
+
+	extension := s.ShellScript.Extension
+	if extension == "" {
+		extension = "sh"
+	}
+
+	s.scriptFile = filepath.Join(scriptDir, "script."+extension)
+	return ioutil.WriteFile(s.scriptFile, s.ShellScript.GetScriptBytes(), 0700)
+}
+
+func (s *CustomExecutor) Prepare(ctx context.Context, globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
+	err := s.AbstractExecutor.Prepare(ctx, globalConfig, config, build)
+	if err != nil {
+		return err
+	}
+
+	if config.Custom == nil || config.Custom.RunExec == "" {
+		return errors.New("Missing custom configuration: run_exec is required")
+	}
+
+	s.Println("Using Custom executor...")
+
+	err = s.writeScript()
+	if err != nil {
+		return err
+	}
+
+	if config.Custom.PrepareExec == "" {
+		return nil
+	}
+
+	s.Debugln("Running prepare program", config.Custom.PrepareExec, "...")
+	cmd := s.newCommand(prepareStage, config.Custom.PrepareExec, config.Custom.PrepareArgs)
+	cmd.Stdout = s.BuildLog
+	cmd.Stderr = s.BuildLog
+
+	err = runCommand(ctx, cmd)
+	if err != nil {
+		return &common.SystemError{Inner: fmt.Errorf("%s program failed: %v", prepareStage, err)}
+	}
+	return nil
+}
+
+func (s *CustomExecutor) Start(ctx context.Context) error {
+	s.Debugln("Starting run program...")
+
+	// The build script is passed as the last argument
+	args := append(append([]string{}, s.Config.Custom.RunArgs...), s.scriptFile)
+	s.cmd = s.newCommand(runStage, s.Config.Custom.RunExec, args)
+	s.cmd.Stdout = s.BuildLog
+	s.cmd.Stderr = s.BuildLog
+
+	err := s.cmd.Start()
+	if err != nil {
+		return &common.SystemError{Inner: fmt.Errorf("failed to start %s program: %v", runStage, err)}
+	}
+
+	// Wait for process to exit
+	go func() {
+		err := waitCommand(ctx, s.cmd)
+		if err != nil {
+			err = s.runFailure(err)
+		}
+		s.BuildFinish <- err
+	}()
+	return nil
+}
+
+func (s *CustomExecutor) Cleanup(ctx context.Context) {
+	helpers.KillProcessGroup(s.cmd)
+
+	if s.Config != nil && s.Config.Custom != nil && s.Config.Custom.CleanupExec != "" {
+		var output bytes.Buffer
+		cmd := s.newCommand(cleanupStage, s.Config.Custom.CleanupExec, s.Config.Custom.CleanupArgs)
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		err := runCommand(ctx, cmd)
+		s.Debugln("Cleanup program finished with", err, output.String())
+	}
+
+	if s.scriptDir != "" {
+		os.RemoveAll(s.scriptDir)
+	}
+
+	s.AbstractExecutor.Cleanup(ctx)
+}
+
+func init() {
+	options := executors.ExecutorOptions{
+		DefaultBuildsDir: "builds",
+		SharedBuildsDir:  false,
+		Shell: common.ShellScriptInfo{
+			Shell: "bash",
+			Type:  common.NormalShell,
+		},
+		ShowHostname: false,
+	}
+
+	create := func() common.Executor {
+		return &CustomExecutor{
+			AbstractExecutor: executors.AbstractExecutor{
+				ExecutorOptions: options,
+			},
+		}
+	}
+
+	common.RegisterExecutor("custom", common.ExecutorFactory{
+		Create: create,
+		Features: common.FeaturesInfo{
+			Variables: true,
+		},
+	})
+}
@@ -0,0 +1,126 @@
+// +build linux darwin
+
+package custom
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+func writeProgram(t *testing.T, dir, name, script string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func runCustomBuild(t *testing.T, ctx context.Context, runScript string) (*common.Build, string, error) {
+	return runCustomBuildWithTimeout(t, ctx, runScript, 0)
+}
+
+func runCustomBuildWithTimeout(t *testing.T, ctx context.Context, runScript string, timeout int) (*common.Build, string, error) {
+	dir, err := ioutil.TempDir("", "custom_executor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	coordinator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer coordinator.Close()
+
+	cleanupFile := filepath.Join(dir, "cleanup")
+	build := &common.Build{
+		GetBuildResponse: common.GetBuildResponse{
+			ID:        1,
+			ProjectID: 2,
+			Commands:  "make test",
+			RepoURL:   "https://gitlab.com/gitlab-org/gitlab-ce.git",
+			Sha:       "1234567890abcdef",
+			RefName:   "master",
+			Timeout:   timeout,
+			Variables: []common.BuildVariable{
+				{Key: "MY_VARIABLE", Value: "my-value", Public: true},
+			},
+		},
+		Runner: &common.RunnerConfig{
+			RunnerCredentials: common.RunnerCredentials{
+				URL:   coordinator.URL,
+				Token: "runner-token",
+			},
+			Executor: "custom",
+			Custom: &common.CustomConfig{
+				PrepareExec: writeProgram(t, dir, "prepare", `echo "prepare $MY_VARIABLE $RUNNER_STAGE"`),
+				RunExec:     writeProgram(t, dir, "run", runScript),
+				CleanupExec: writeProgram(t, dir, "cleanup", `echo "$RUNNER_BUILD_DIR" > `+cleanupFile),
+			},
+		},
+	}
+
+	err = build.Run(ctx, common.NewConfig())
+	cleanup, _ := ioutil.ReadFile(cleanupFile)
+	return build, string(cleanup), err
+}
+
+func TestCustomBuild(t *testing.T) {
+	build, cleanup, err := runCustomBuild(t, context.Background(), `test -f "$1" && test "$1" = "$RUNNER_SCRIPT_FILE" && echo "run $MY_VARIABLE"`)
+	assert.NoError(t, err)
+	assert.Equal(t, common.BuildState(common.Success), build.BuildState)
+	assert.Contains(t, build.BuildLog(), "prepare my-value prepare")
+	assert.Contains(t, build.BuildLog(), "run my-value")
+	assert.Equal(t, build.FullProjectDir()+"\n", cleanup)
+}
+
+func TestCustomBuildFailure(t *testing.T) {
+	build, _, err := runCustomBuild(t, context.Background(), "exit 1")
+	assert.EqualError(t, err, "exit code 1")
+	_, isSystemError := err.(*common.SystemError)
+	assert.False(t, isSystemError)
+	assert.Equal(t, common.BuildState(common.Failed), build.BuildState)
+}
+
+func TestCustomSystemFailure(t *testing.T) {
+	build, cleanup, err := runCustomBuild(t, context.Background(), "exit 2")
+	assert.IsType(t, &common.SystemError{}, err)
+	assert.Contains(t, build.BuildLog(), "system failure")
+	assert.NotEmpty(t, cleanup)
+}
+
+func TestCustomBuildAborted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(time.Second, cancel)
+
+	started := time.Now()
+	build, cleanup, err := runCustomBuild(t, ctx, "sleep 30")
+	assert.NoError(t, err)
+	assert.True(t, time.Since(started) < 30*time.Second)
+	assert.Equal(t, common.BuildState(common.Failed), build.BuildState)
+	assert.Contains(t, build.BuildLog(), "Build got aborted")
+	assert.NotEmpty(t, cleanup)
+}
+
+func TestCustomBuildTimeout(t *testing.T) {
+	started := time.Now()
+	build, cleanup, err := runCustomBuildWithTimeout(t, context.Background(), "sleep 30", 1)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(started) < 30*time.Second)
+	assert.Equal(t, common.BuildState(common.Failed), build.BuildState)
+	assert.Contains(t, build.BuildLog(), "CI Timeout")
+	assert.NotEmpty(t, cleanup)
+}
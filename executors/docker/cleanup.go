@@ -0,0 +1,197 @@
+package docker
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fsouza/go-dockerclient"
+	log "github.com/sirupsen/logrus"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers/docker"
+)
+
+// CleanupOptions controls removal of containers left behind by builds
+// that were not cleaned up, eg. when runner crashed or was killed
+type CleanupOptions struct {
+	MaxAge      time.Duration `long:"max-age" description:"Remove build, service and wait containers older than this (should be longer than build timeout)"`
+	CacheMaxAge time.Duration `long:"cache-max-age" description:"Remove cache containers and their volumes not used for longer than this, 0 keeps caches forever"`
+	DryRun      bool          `long:"dry-run" description:"Only print containers that would be removed"`
+
+	// IsBuildRunning allows to skip containers of builds that are still processed
+	IsBuildRunning func(buildID int) bool
+}
+
+var errRemoveFailed = errors.New("failed to remove some of containers")
+
+type dockerCleanup struct {
+	CleanupOptions
+	client   *docker.Client
+	runnerID string
+	now      time.Time
+}
+
+func (c *dockerCleanup) listContainers() ([]*docker.Container, error) {
+	runnerLabel := dockerLabelPrefix + ".runner.id=" + c.runnerID
+	apiContainers, err := c.client.ListContainers(docker.ListContainersOptions{
+		All: true,
+		Filters: map[string][]string{
+			"label": {runnerLabel},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []*docker.Container
+	for _, apiContainer := range apiContainers {
+		// older daemons ignore filters
+		if apiContainer.Labels[dockerLabelPrefix+".runner.id"] != c.runnerID {
+			continue
+		}
+
+		container, err := c.client.InspectContainer(apiContainer.ID)
+		if _, ok := err.(*docker.NoSuchContainer); ok {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+func containerLabel(container *docker.Container, name string) string {
+	if container.Config == nil {
+		return ""
+	}
+	return container.Config.Labels[dockerLabelPrefix+"."+name]
+}
+
+func (c *dockerCleanup) isOrphaned(container *docker.Container) bool {
+	if c.IsBuildRunning != nil {
+		buildID, err := strconv.Atoi(containerLabel(container, "build.id"))
+		if err == nil && c.IsBuildRunning(buildID) {
+			return false
+		}
+	}
+	return c.now.Sub(container.Created) >= c.MaxAge
+}
+
+func (c *dockerCleanup) isUnusedCache(container *docker.Container, usedContainers map[string]bool) bool {
+	if c.CacheMaxAge <= 0 {
+		return false
+	}
+	if usedContainers[container.ID] || usedContainers[strings.TrimPrefix(container.Name, "/")] {
+		return false
+	}
+
+	// cache container is restarted when it's reused by build
+	lastUsed := container.State.FinishedAt
+	if lastUsed.Before(container.Created) {
+		lastUsed = container.Created
+	}
+	return c.now.Sub(lastUsed) >= c.CacheMaxAge
+}
+
+func (c *dockerCleanup) remove(container *docker.Container, reason string) error {
+	logger := log.WithFields(log.Fields{
+		"runner":    c.runnerID,
+		"container": strings.TrimPrefix(container.Name, "/"),
+		"type":      containerLabel(container, "type"),
+		"build":     containerLabel(container, "build.id"),
+	})
+
+	if c.DryRun {
+		logger.Println("Would remove", reason, "container")
+		return nil
+	}
+
+	err := c.client.RemoveContainer(docker.RemoveContainerOptions{
+		ID:            container.ID,
+		RemoveVolumes: true,
+		Force:         true,
+	})
+	if _, ok := err.(*docker.NoSuchContainer); ok {
+		return nil
+	} else if err != nil {
+		logger.Warningln("Failed to remove", reason, "container:", err)
+		return err
+	}
+
+	logger.Println("Removed", reason, "container")
+	return nil
+}
+
+func (c *dockerCleanup) run() (removed int, err error) {
+	containers, err := c.listContainers()
+	if err != nil {
+		return 0, err
+	}
+
+	var caches []*docker.Container
+	usedContainers := make(map[string]bool)
+
+	for _, container := range containers {
+		// temporary caches are checked like the other containers of build
+		if containerLabel(container, "type") == "cache" {
+			caches = append(caches, container)
+			continue
+		}
+
+		if !c.isOrphaned(container) {
+			if container.HostConfig == nil {
+				continue
+			}
+
+			// cache containers used by kept containers can't be removed
+			for _, volumesFrom := range container.HostConfig.VolumesFrom {
+				usedContainers[strings.SplitN(volumesFrom, ":", 2)[0]] = true
+			}
+			continue
+		}
+
+		if c.remove(container, "orphaned") != nil {
+			err = errRemoveFailed
+			continue
+		}
+		removed++
+	}
+
+	for _, container := range caches {
+		if !c.isUnusedCache(container, usedContainers) {
+			continue
+		}
+
+		if c.remove(container, "unused cache") != nil {
+			err = errRemoveFailed
+			continue
+		}
+		removed++
+	}
+	return
+}
+
+// Cleanup removes containers of runner that were left behind
+// and caches that were not used for a specified time
+func Cleanup(config *common.RunnerConfig, options CleanupOptions) (int, error) {
+	if config.Docker == nil {
+		return 0, nil
+	}
+
+	client, _, err := docker_helpers.ConnectNegotiated(config.Docker.DockerCredentials,
+		dockerMinAPIVersion, dockerAPIVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	cleanup := &dockerCleanup{
+		CleanupOptions: options,
+		client:         client,
+		runnerID:       config.ShortDescription(),
+		now:            time.Now(),
+	}
+	return cleanup.run()
+}
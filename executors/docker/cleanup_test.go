@@ -0,0 +1,169 @@
+package docker
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fsouza/go-dockerclient"
+	dtesting "github.com/fsouza/go-dockerclient/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCleanupServer struct {
+	*dtesting.DockerServer
+	containers []*docker.Container
+	removed    []string
+	lock       sync.Mutex
+}
+
+func (f *fakeCleanupServer) addContainer(container *docker.Container, runnerID string, labels ...string) {
+	container.Config = &docker.Config{
+		Labels: map[string]string{
+			dockerLabelPrefix + ".runner.id": runnerID,
+		},
+	}
+	for idx := 0; idx+1 < len(labels); idx += 2 {
+		container.Config.Labels[dockerLabelPrefix+"."+labels[idx]] = labels[idx+1]
+	}
+	f.containers = append(f.containers, container)
+
+	f.CustomHandler("^/containers/"+container.ID+"/json$", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(container)
+	}))
+	f.CustomHandler("^/containers/"+container.ID+"$", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			http.NotFound(w, r)
+			return
+		}
+
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		f.removed = append(f.removed, container.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func (f *fakeCleanupServer) listContainers(w http.ResponseWriter, r *http.Request) {
+	var result []docker.APIContainers
+	for _, container := range f.containers {
+		result = append(result, docker.APIContainers{
+			ID:     container.ID,
+			Labels: container.Config.Labels,
+		})
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+func newFakeCleanup(t *testing.T, options CleanupOptions) (*fakeCleanupServer, *dockerCleanup) {
+	server, err := dtesting.NewServer("127.0.0.1:0", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fakeServer := &fakeCleanupServer{DockerServer: server}
+	server.CustomHandler("^/containers/json$", http.HandlerFunc(fakeServer.listContainers))
+
+	client, err := docker.NewClient(server.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cleanup := &dockerCleanup{
+		CleanupOptions: options,
+		client:         client,
+		runnerID:       "runner",
+		now:            time.Now(),
+	}
+	return fakeServer, cleanup
+}
+
+func TestCleanupOrphanedContainers(t *testing.T) {
+	server, cleanup := newFakeCleanup(t, CleanupOptions{
+		MaxAge: time.Hour,
+		IsBuildRunning: func(buildID int) bool {
+			return buildID == 3
+		},
+	})
+	defer server.Stop()
+
+	now := cleanup.now
+	server.addContainer(&docker.Container{ID: "old-build", Created: now.Add(-2 * time.Hour)}, "runner", "type", "build", "build.id", "1")
+	server.addContainer(&docker.Container{ID: "old-service", Created: now.Add(-2 * time.Hour)}, "runner", "type", "service", "build.id", "1")
+	server.addContainer(&docker.Container{ID: "new-build", Created: now.Add(-time.Minute)}, "runner", "type", "build", "build.id", "2")
+	server.addContainer(&docker.Container{ID: "running-build", Created: now.Add(-2 * time.Hour)}, "runner", "type", "build", "build.id", "3")
+	server.addContainer(&docker.Container{ID: "old-temporary-cache", Created: now.Add(-2 * time.Hour)}, "runner", "type", "cache-temporary", "build.id", "1")
+	server.addContainer(&docker.Container{ID: "running-temporary-cache", Created: now.Add(-2 * time.Hour)}, "runner", "type", "cache-temporary", "build.id", "3")
+	server.addContainer(&docker.Container{ID: "other-runner", Created: now.Add(-2 * time.Hour)}, "other", "type", "build", "build.id", "1")
+
+	removed, err := cleanup.run()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, removed)
+
+	sort.Strings(server.removed)
+	assert.Equal(t, []string{"old-build", "old-service", "old-temporary-cache"}, server.removed)
+}
+
+func TestCleanupUnusedCaches(t *testing.T) {
+	server, cleanup := newFakeCleanup(t, CleanupOptions{
+		MaxAge:      time.Hour,
+		CacheMaxAge: 24 * time.Hour,
+	})
+	defer server.Stop()
+
+	now := cleanup.now
+	server.addContainer(&docker.Container{
+		ID:      "unused-cache",
+		Created: now.Add(-72 * time.Hour),
+		State:   docker.State{FinishedAt: now.Add(-48 * time.Hour)},
+	}, "runner", "type", "cache")
+	server.addContainer(&docker.Container{
+		ID:      "recently-used-cache",
+		Created: now.Add(-72 * time.Hour),
+		State:   docker.State{FinishedAt: now.Add(-time.Hour)},
+	}, "runner", "type", "cache")
+	server.addContainer(&docker.Container{
+		ID:      "cache-used-by-build",
+		Created: now.Add(-72 * time.Hour),
+	}, "runner", "type", "cache")
+	server.addContainer(&docker.Container{
+		ID:         "build",
+		Created:    now,
+		HostConfig: &docker.HostConfig{VolumesFrom: []string{"cache-used-by-build"}},
+	}, "runner", "type", "build", "build.id", "1")
+
+	removed, err := cleanup.run()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, []string{"unused-cache"}, server.removed)
+}
+
+func TestCleanupKeepsCachesByDefault(t *testing.T) {
+	server, cleanup := newFakeCleanup(t, CleanupOptions{})
+	defer server.Stop()
+
+	server.addContainer(&docker.Container{ID: "cache", Created: cleanup.now.Add(-72 * time.Hour)}, "runner", "type", "cache")
+
+	removed, err := cleanup.run()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, removed)
+	assert.Empty(t, server.removed)
+}
+
+func TestCleanupDryRun(t *testing.T) {
+	server, cleanup := newFakeCleanup(t, CleanupOptions{
+		MaxAge: time.Hour,
+		DryRun: true,
+	})
+	defer server.Stop()
+
+	server.addContainer(&docker.Container{ID: "old-build", Created: cleanup.now.Add(-2 * time.Hour)}, "runner", "type", "build", "build.id", "1")
+
+	removed, err := cleanup.run()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Empty(t, server.removed)
+}
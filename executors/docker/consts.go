@@ -2,6 +2,23 @@ package docker
 
 import "time"
 
-const dockerAPIVersion = "1.18"
+const dockerAPIVersion = "1.24"
+const dockerMinAPIVersion = "1.22"
 const dockerImageTTL = time.Minute
-const dockerLabelPrefix = "com.gitlab.gitlab-runner"
\ No newline at end of file
+const dockerImageCacheFile = "docker-images.json"
+const dockerCacheTouchInterval = time.Hour
+const dockerServiceProbeInterval = time.Second
+const dockerCPUPeriod = 100000
+const dockerHelperImage = "gitlab/gitlab-runner:helper"
+const dockerLabelPrefix = "com.gitlab.gitlab-runner"
+
+// dockerSupportedOptions are the build options accepted by both docker and docker-ssh executors
+var dockerSupportedOptions = []string{"image", "services", "pull_policy", "cap_add", "cap_drop", "security_opt", "devices", "tmpfs", "sysctls", "userns_mode", "read_only"}
+
+const (
+	PullPolicyAlways       = "always"
+	PullPolicyIfNotPresent = "if-not-present"
+	PullPolicyNever        = "never"
+)
+
+const oomKilledMessage = "container was killed because it ran out of memory (OOMKilled), consider increasing the memory limit"
@@ -1,12 +1,15 @@
 package docker
 
 import (
+	"context"
 	"crypto/md5"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	u "os/user"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,14 +26,143 @@ import (
 
 type DockerExecutor struct {
 	executors.AbstractExecutor
-	client         *docker.Client
-	buildContainer *docker.Container
-	services       []*docker.Container
-	caches         []*docker.Container
+	client           *docker.Client
+	buildContainer   *docker.Container
+	buildImage       *docker.Image
+	scriptContainers []*docker.Container
+	binds            []string
+	volumesFrom      []string
+	services         []*docker.Container
+	caches           []*docker.Container
+	failures         []string
+	network          *docker.Network
+	pullPolicy       string
+	pulledImages     *PulledImageCache
+	security         *securityOptions
+	apiVersion       docker.APIVersion
 }
 
-func (s *DockerExecutor) getServiceVariables() []string {
-	variables := s.Config.Environment
+type resourceLimits struct {
+	memory     string
+	memorySwap string
+	cpus       string
+	cpusetCPUs string
+	pidsLimit  int64
+	shmSize    string
+}
+
+func (s *DockerExecutor) getBuildResourceLimits() resourceLimits {
+	return resourceLimits{
+		memory:     s.Config.Docker.Memory,
+		memorySwap: s.Config.Docker.MemorySwap,
+		cpus:       s.Config.Docker.CPUs,
+		cpusetCPUs: s.Config.Docker.CPUSetCPUs,
+		pidsLimit:  s.Config.Docker.PidsLimit,
+		shmSize:    s.Config.Docker.ShmSize,
+	}
+}
+
+func (s *DockerExecutor) getServiceResourceLimits() resourceLimits {
+	return resourceLimits{
+		memory:     s.Config.Docker.ServiceMemory,
+		memorySwap: s.Config.Docker.ServiceMemorySwap,
+		cpus:       s.Config.Docker.ServiceCPUs,
+		cpusetCPUs: s.Config.Docker.ServiceCPUSetCPUs,
+		pidsLimit:  s.Config.Docker.ServicePidsLimit,
+		shmSize:    s.Config.Docker.ServiceShmSize,
+	}
+}
+
+func setResourceLimits(hostConfig *docker.HostConfig, limits resourceLimits) (err error) {
+	if limits.memory != "" {
+		hostConfig.Memory, err = docker_helpers.ParseSize(limits.memory)
+		if err != nil {
+			return fmt.Errorf("memory: %v", err)
+		}
+	}
+
+	if limits.memorySwap == "-1" {
+		hostConfig.MemorySwap = -1
+	} else if limits.memorySwap != "" {
+		hostConfig.MemorySwap, err = docker_helpers.ParseSize(limits.memorySwap)
+		if err != nil {
+			return fmt.Errorf("memory_swap: %v", err)
+		}
+	}
+
+	if limits.cpus != "" {
+		hostConfig.CPUPeriod = dockerCPUPeriod
+		hostConfig.CPUQuota, err = docker_helpers.ParseCPUs(limits.cpus, dockerCPUPeriod)
+		if err != nil {
+			return fmt.Errorf("cpus: %v", err)
+		}
+	}
+
+	if limits.shmSize != "" {
+		hostConfig.ShmSize, err = docker_helpers.ParseSize(limits.shmSize)
+		if err != nil {
+			return fmt.Errorf("shm_size: %v", err)
+		}
+	}
+
+	hostConfig.CPUSetCPUs = limits.cpusetCPUs
+	hostConfig.PidsLimit = limits.pidsLimit
+	return nil
+}
+
+func (s *DockerExecutor) requireAPIVersion(option, version string) error {
+	required, err := docker.NewAPIVersion(version)
+	if err != nil {
+		return err
+	}
+
+	if s.apiVersion.LessThan(required) {
+		return fmt.Errorf("The %s requires Docker API version %s or newer, but the Docker daemon supports only %s",
+			option, required, s.apiVersion)
+	}
+	return nil
+}
+
+// verifyAPIVersion checks that the options that need newer Docker are used only when
+// the Docker daemon supports them, the other options work with dockerMinAPIVersion
+func (s *DockerExecutor) verifyAPIVersion() error {
+	if s.Config.Docker.PidsLimit != 0 || s.Config.Docker.ServicePidsLimit != 0 {
+		err := s.requireAPIVersion("pids_limit", "1.23")
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.security.usernsMode != "" {
+		err := s.requireAPIVersion("userns_mode", "1.23")
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(s.security.sysctls) > 0 {
+		err := s.requireAPIVersion("sysctls", "1.24")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DockerExecutor) isOOMKilled(ctx context.Context, containerID string) bool {
+	container, err := s.client.InspectContainerWithContext(containerID, ctx)
+	return err == nil && container.State.OOMKilled
+}
+
+func (s *DockerExecutor) getContainerExitError(ctx context.Context, containerID string, exitCode int) error {
+	if s.isOOMKilled(ctx, containerID) {
+		return fmt.Errorf("exit code %d: %s", exitCode, oomKilledMessage)
+	}
+	return fmt.Errorf("exit code %d", exitCode)
+}
+
+func (s *DockerExecutor) getServiceVariables(service common.DockerService) []string {
+	variables := append([]string{}, s.Config.Environment...)
 
 	for _, buildVariable := range s.Build.Variables {
 		if !buildVariable.Public {
@@ -41,47 +173,166 @@ func (s *DockerExecutor) getServiceVariables() []string {
 		variables = append(variables, variable)
 	}
 
+	var names []string
+	for name := range service.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		variables = append(variables, name+"="+service.Variables[name])
+	}
+
 	return variables
 }
 
-func (s *DockerExecutor) getAuthConfig(imageName string) (docker.AuthConfiguration, error) {
+func (s *DockerExecutor) getVariable(key string) (value string) {
+	for _, environment := range s.Config.Environment {
+		keyValue := strings.SplitN(environment, "=", 2)
+		if len(keyValue) == 2 && keyValue[0] == key {
+			value = keyValue[1]
+		}
+	}
+
+	// build variables overwrite runner environment
+	for _, variable := range s.Build.Variables {
+		if variable.Key == key {
+			value = variable.Value
+		}
+	}
+	return
+}
+
+func (s *DockerExecutor) getUserAuthConfigs() (*docker_helpers.DockerAuthConfig, error) {
 	user, err := u.Current()
 	if s.Shell.User != nil {
 		user, err = u.Lookup(*s.Shell.User)
 	}
 	if err != nil {
-		return docker.AuthConfiguration{}, err
+		return nil, err
 	}
 
-	indexName, _ := docker_helpers.SplitDockerImageName(imageName)
-
 	authConfigs, err := docker_helpers.ReadDockerAuthConfigs(user.HomeDir)
-	if err != nil {
+	if os.IsNotExist(err) {
 		// ignore doesn't exist errors
-		if os.IsNotExist(err) {
-			err = nil
+		return nil, nil
+	}
+	return authConfigs, err
+}
+
+func (s *DockerExecutor) getAuthConfig(imageName string) (docker.AuthConfiguration, error) {
+	indexName, _ := docker_helpers.SplitDockerImageName(imageName)
+
+	var sources []string
+	var authConfigs []*docker_helpers.DockerAuthConfig
+
+	if authConfigValue := s.getVariable("DOCKER_AUTH_CONFIG"); authConfigValue != "" {
+		authConfig, err := docker_helpers.ParseDockerAuthConfig(strings.NewReader(authConfigValue))
+		if err != nil {
+			s.Warningln("Failed to parse DOCKER_AUTH_CONFIG:", err)
+		} else {
+			// the variable can be set by the build, so it can use only the helpers allowed by the runner
+			for _, helper := range authConfig.RestrictCredentialHelpers(s.Config.Docker.AllowedCredentialHelpers) {
+				s.Warningln("The credential helper", helper, "of DOCKER_AUTH_CONFIG is not present on list of allowed_credential_helpers")
+			}
+			sources = append(sources, "DOCKER_AUTH_CONFIG")
+			authConfigs = append(authConfigs, authConfig)
 		}
-		return docker.AuthConfiguration{}, err
 	}
 
-	authConfig := docker_helpers.ResolveDockerAuthConfig(indexName, authConfigs)
-	if authConfig != nil {
-		s.Debugln("Using", authConfig.Username, "to connect to", authConfig.ServerAddress, "in order to resolve", imageName, "...")
-		return *authConfig, nil
+	userAuthConfig, err := s.getUserAuthConfigs()
+	if err != nil {
+		s.Debugln("Failed to read docker configuration of user:", err)
+	} else if userAuthConfig != nil {
+		sources = append(sources, "user configuration")
+		authConfigs = append(authConfigs, userAuthConfig)
+	}
+
+	for idx, config := range authConfigs {
+		authConfig, err := config.Resolve(indexName)
+		if err != nil {
+			s.Warningln("Failed to get credentials for", indexName, "from", sources[idx], ":", err)
+		}
+		if authConfig != nil {
+			s.Debugln("Using", authConfig.Username, "from", sources[idx], "to connect to", authConfig.ServerAddress, "in order to resolve", imageName, "...")
+			return *authConfig, nil
+		}
 	}
 
 	return docker.AuthConfiguration{}, fmt.Errorf("No credentials found for %v", indexName)
 }
 
-func (s *DockerExecutor) getDockerImage(imageName string) (*docker.Image, error) {
+func (s *DockerExecutor) getPullPolicy() (string, error) {
+	pullPolicy := s.Config.Docker.PullPolicy
+
+	if pullPolicyOption, ok := s.Build.Options["pull_policy"].(string); ok && pullPolicyOption != "" {
+		allowed := false
+		for _, allowedPullPolicy := range s.Config.Docker.AllowedPullPolicies {
+			if allowedPullPolicy == pullPolicyOption {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			s.Errorln("The", pullPolicyOption, "is not present on list of allowed pull policies:", s.Config.Docker.AllowedPullPolicies)
+			return "", errors.New("invalid pull policy")
+		}
+		pullPolicy = pullPolicyOption
+	}
+
+	switch pullPolicy {
+	case "", PullPolicyAlways, PullPolicyIfNotPresent, PullPolicyNever:
+		return pullPolicy, nil
+	default:
+		return "", fmt.Errorf("unsupported pull policy: %s", pullPolicy)
+	}
+}
+
+func (s *DockerExecutor) getImageTTL() time.Duration {
+	if s.Config.Docker.ImageTTL != nil {
+		return time.Duration(*s.Config.Docker.ImageTTL) * time.Second
+	}
+	return dockerImageTTL
+}
+
+func (s *DockerExecutor) getImageCacheFile(globalConfig *common.Config) string {
+	if s.Config.Docker.ImageCacheFile != nil {
+		return *s.Config.Docker.ImageCacheFile
+	}
+
+	// by default store it next to config file
+	if globalConfig != nil && globalConfig.ConfigFile != "" {
+		return filepath.Join(filepath.Dir(globalConfig.ConfigFile), dockerImageCacheFile)
+	}
+	return ""
+}
+
+func (s *DockerExecutor) getDockerImage(ctx context.Context, imageName string) (*docker.Image, error) {
 	if !strings.Contains(imageName, ":") {
 		imageName = imageName + ":latest"
 	}
 
 	s.Debugln("Looking for image", imageName, "...")
 	image, err := s.client.InspectImage(imageName)
-	if err == nil {
-		if !pulledImageCache.isExpired(imageName) {
+	imageKey := pulledImageKey(docker_helpers.Endpoint(s.Config.Docker.DockerCredentials), imageName)
+
+	switch s.pullPolicy {
+	case PullPolicyNever:
+		if err != nil {
+			return nil, fmt.Errorf("image %s not found locally and pull policy is %s: %v", imageName, PullPolicyNever, err)
+		}
+		return image, nil
+
+	case PullPolicyIfNotPresent:
+		if err == nil {
+			return image, nil
+		}
+
+	case PullPolicyAlways:
+
+	default:
+		if err == nil && !s.pulledImages.isExpired(imageKey) {
 			return image, nil
 		}
 	}
@@ -94,11 +345,13 @@ func (s *DockerExecutor) getDockerImage(imageName string) (*docker.Image, error)
 
 	pullImageOptions := docker.PullImageOptions{
 		Repository: imageName,
+		Context:    ctx,
 	}
 
 	err = s.client.PullImage(pullImageOptions, authConfig)
 	if err != nil {
-		if image != nil {
+		// with always pull policy the local image is never used
+		if image != nil && s.pullPolicy != PullPolicyAlways {
 			s.Warningln("Cannot pull the latest version of image", imageName, ":", err)
 			s.Warningln("Locally found image will be used instead.")
 			return image, nil
@@ -112,7 +365,10 @@ func (s *DockerExecutor) getDockerImage(imageName string) (*docker.Image, error)
 		return nil, err
 	}
 
-	pulledImageCache.mark(imageName, image.ID, dockerImageTTL)
+	err = s.pulledImages.mark(imageKey, image.ID, s.getImageTTL())
+	if err != nil {
+		s.Debugln("Failed to store pulled image in cache:", err)
+	}
 	return image, nil
 }
 
@@ -151,13 +407,19 @@ func (s *DockerExecutor) getLabels(containerType string, otherLabels ...string)
 	return labels
 }
 
-func (s *DockerExecutor) createCacheVolume(containerName, containerPath string) (*docker.Container, error) {
+func (s *DockerExecutor) createCacheVolume(ctx context.Context, containerName, containerPath string) (*docker.Container, error) {
 	// get busybox image
-	cacheImage, err := s.getDockerImage("gitlab/gitlab-runner:cache")
+	cacheImage, err := s.getDockerImage(ctx, "gitlab/gitlab-runner:cache")
 	if err != nil {
 		return nil, err
 	}
 
+	// unnamed cache is used only by single build, docker-cleanup removes it with the other containers of build
+	containerType := "cache"
+	if containerName == "" {
+		containerType = "cache-temporary"
+	}
+
 	createContainerOptions := docker.CreateContainerOptions{
 		Name: containerName,
 		Config: &docker.Config{
@@ -168,42 +430,67 @@ func (s *DockerExecutor) createCacheVolume(containerName, containerPath string)
 			Volumes: map[string]struct{}{
 				containerPath: {},
 			},
-			Labels: s.getLabels("cache", "cache.dir="+containerPath),
+			Labels: s.getLabels(containerType, "cache.dir="+containerPath),
 		},
 		HostConfig: &docker.HostConfig{},
+		Context:    ctx,
 	}
 
 	container, err := s.client.CreateContainer(createContainerOptions)
 	if err != nil {
 		if container != nil {
-			go s.removeContainer(container.ID)
+			s.failures = append(s.failures, container.ID)
 		}
 		return nil, err
 	}
 
 	s.Debugln("Starting cache container", container.ID, "...")
-	err = s.client.StartContainer(container.ID, nil)
+	err = s.client.StartContainerWithContext(container.ID, nil, ctx)
 	if err != nil {
-		go s.removeContainer(container.ID)
+		s.failures = append(s.failures, container.ID)
 		return nil, err
 	}
 
 	s.Debugln("Waiting for cache container", container.ID, "...")
-	errorCode, err := s.client.WaitContainer(container.ID)
+	errorCode, err := s.client.WaitContainerWithContext(container.ID, ctx)
 	if err != nil {
-		go s.removeContainer(container.ID)
+		s.failures = append(s.failures, container.ID)
 		return nil, err
 	}
 
 	if errorCode != 0 {
-		go s.removeContainer(container.ID)
+		s.failures = append(s.failures, container.ID)
 		return nil, fmt.Errorf("cache container for %s returned %d", containerPath, errorCode)
 	}
 
 	return container, nil
 }
 
-func (s *DockerExecutor) addCacheVolume(binds, volumesFrom *[]string, containerPath string) error {
+func (s *DockerExecutor) touchCacheVolume(ctx context.Context, container *docker.Container) {
+	s.Debugln("Restarting cache container", container.ID, "...")
+	err := s.client.StartContainerWithContext(container.ID, nil, ctx)
+	if err == nil {
+		_, err = s.client.WaitContainerWithContext(container.ID, ctx)
+	}
+	if err != nil {
+		s.Debugln("Failed to restart cache container", container.ID, "with", err)
+	}
+}
+
+func hasVolume(container *docker.Container, containerPath string) bool {
+	if container.Volumes[containerPath] != "" {
+		return true
+	}
+
+	for _, mount := range container.Mounts {
+		if mount.Destination == containerPath {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *DockerExecutor) addCacheVolume(ctx context.Context, binds, volumesFrom *[]string, containerPath string) error {
 	var err error
 	containerPath = s.getAbsoluteContainerPath(containerPath)
 
@@ -229,17 +516,23 @@ func (s *DockerExecutor) addCacheVolume(binds, volumesFrom *[]string, containerP
 
 	// get existing cache container
 	containerName := fmt.Sprintf("%s-cache-%x", s.Build.ProjectUniqueName(), hash)
-	container, _ := s.client.InspectContainer(containerName)
+	container, _ := s.client.InspectContainerWithContext(containerName, ctx)
 
 	// check if we have valid cache, if not remove the broken container
-	if container != nil && container.Volumes[containerPath] == "" {
-		s.removeContainer(container.ID)
+	if container != nil && !hasVolume(container, containerPath) {
+		s.removeContainer(ctx, container.ID)
 		container = nil
 	}
 
+	// restart existing cache container to record when it was last used,
+	// this allows the docker-cleanup to remove not used caches
+	if container != nil && time.Since(container.State.FinishedAt) > dockerCacheTouchInterval {
+		s.touchCacheVolume(ctx, container)
+	}
+
 	// create new cache container for that project
 	if container == nil {
-		container, err = s.createCacheVolume(containerName, containerPath)
+		container, err = s.createCacheVolume(ctx, containerName, containerPath)
 		if err != nil {
 			return err
 		}
@@ -250,7 +543,7 @@ func (s *DockerExecutor) addCacheVolume(binds, volumesFrom *[]string, containerP
 	return nil
 }
 
-func (s *DockerExecutor) addVolume(binds, volumesFrom *[]string, volume string) error {
+func (s *DockerExecutor) addVolume(ctx context.Context, binds, volumesFrom *[]string, volume string) error {
 	var err error
 	hostVolume := strings.SplitN(volume, ":", 2)
 	switch len(hostVolume) {
@@ -259,7 +552,7 @@ func (s *DockerExecutor) addVolume(binds, volumesFrom *[]string, volume string)
 
 	case 1:
 		// disable cache disables
-		err = s.addCacheVolume(binds, volumesFrom, hostVolume[0])
+		err = s.addCacheVolume(ctx, binds, volumesFrom, hostVolume[0])
 	}
 
 	if err != nil {
@@ -268,11 +561,11 @@ func (s *DockerExecutor) addVolume(binds, volumesFrom *[]string, volume string)
 	return err
 }
 
-func (s *DockerExecutor) createVolumes(image *docker.Image) ([]string, []string, error) {
+func (s *DockerExecutor) createVolumes(ctx context.Context, image *docker.Image) ([]string, []string, error) {
 	var binds, volumesFrom []string
 
 	for _, volume := range s.Config.Docker.Volumes {
-		s.addVolume(&binds, &volumesFrom, volume)
+		s.addVolume(ctx, &binds, &volumesFrom, volume)
 	}
 
 	// Cache Git sources:
@@ -284,10 +577,10 @@ func (s *DockerExecutor) createVolumes(image *docker.Image) ([]string, []string,
 	if filepath.IsAbs(parentDir) && parentDir != "/" {
 		if s.Build.AllowGitFetch && !helpers.BoolOrDefault(s.Config.Docker.DisableCache, false) {
 			// create persistent cache container
-			s.addVolume(&binds, &volumesFrom, parentDir)
+			s.addVolume(ctx, &binds, &volumesFrom, parentDir)
 		} else {
 			// create temporary cache container
-			container, _ := s.createCacheVolume("", parentDir)
+			container, _ := s.createCacheVolume(ctx, "", parentDir)
 			if container != nil {
 				s.caches = append(s.caches, container)
 				volumesFrom = append(volumesFrom, container.ID)
@@ -318,33 +611,117 @@ func (s *DockerExecutor) splitServiceAndVersion(serviceDescription string) (stri
 	return service, version, linkName
 }
 
-func (s *DockerExecutor) createService(service, version string) (*docker.Container, error) {
-	if len(service) == 0 {
+func getServiceAliases(linkName string) []string {
+	aliases := []string{linkName}
+
+	// double underscores are not valid in host names
+	if alias := strings.Replace(linkName, "__", "-", -1); alias != linkName {
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
+
+func (s *DockerExecutor) removeNetwork(ctx context.Context, id string) error {
+	network, err := s.client.NetworkInfo(id)
+	if err != nil {
+		return err
+	}
+
+	// network can't be removed when there are still containers connected
+	for containerID := range network.Containers {
+		s.client.DisconnectNetwork(network.ID, docker.NetworkConnectionOptions{
+			Container: containerID,
+			Force:     true,
+			Context:   ctx,
+		})
+	}
+
+	err = s.client.RemoveNetwork(network.ID)
+	s.Debugln("Removed network", network.Name, "with", err)
+	return err
+}
+
+func (s *DockerExecutor) createNetwork(ctx context.Context) error {
+	networkName := s.Build.ProjectUniqueName()
+
+	// this will fail potentially some builds if there's name collision
+	s.removeNetwork(ctx, networkName)
+
+	s.Debugln("Creating network", networkName, "...")
+	network, err := s.client.CreateNetwork(docker.CreateNetworkOptions{
+		Name:           networkName,
+		CheckDuplicate: true,
+		Driver:         "bridge",
+		Context:        ctx,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.network = network
+	return nil
+}
+
+func (s *DockerExecutor) connectToNetwork(ctx context.Context, containerID string, aliases []string) error {
+	s.Debugln("Connecting container", containerID, "to network", s.network.Name, "as", aliases, "...")
+	return s.client.ConnectNetwork(s.network.ID, docker.NetworkConnectionOptions{
+		Container: containerID,
+		EndpointConfig: &docker.EndpointConfig{
+			Aliases: aliases,
+		},
+		Context: ctx,
+	})
+}
+
+func (s *DockerExecutor) connectLinkedContainers(ctx context.Context) error {
+	for _, link := range s.Config.Docker.Links {
+		nameAlias := strings.SplitN(link, ":", 2)
+		aliases := []string{nameAlias[len(nameAlias)-1]}
+
+		err := s.connectToNetwork(ctx, nameAlias[0], aliases)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DockerExecutor) createService(ctx context.Context, service common.DockerService, image, version, linkName string) (*docker.Container, error) {
+	if len(image) == 0 {
 		return nil, errors.New("invalid service name")
 	}
 
-	serviceImage, err := s.getDockerImage(service + ":" + version)
+	serviceImage, err := s.getDockerImage(ctx, image+":"+version)
 	if err != nil {
 		return nil, err
 	}
 
-	containerName := s.Build.ProjectUniqueName() + "-" + strings.Replace(service, "/", "__", -1)
+	containerName := s.Build.ProjectUniqueName() + "-" + linkName
 
 	// this will fail potentially some builds if there's name collision
-	s.removeContainer(containerName)
+	s.removeContainer(ctx, containerName)
 
-	s.Println("Starting service", service+":"+version, "...")
+	s.Println("Starting service", image+":"+version, "...")
 	createContainerOpts := docker.CreateContainerOptions{
 		Name: containerName,
 		Config: &docker.Config{
-			Image:  serviceImage.ID,
-			Labels: s.getLabels("service", "service="+service, "service.version="+version),
-			Env:    s.getServiceVariables(),
+			Image:      serviceImage.ID,
+			Labels:     s.getLabels("service", "service="+image, "service.version="+version),
+			Env:        s.getServiceVariables(service),
+			Entrypoint: service.Entrypoint,
+			Cmd:        service.Command,
 		},
 		HostConfig: &docker.HostConfig{
 			RestartPolicy: docker.NeverRestart(),
 		},
+		Context: ctx,
+	}
+
+	err = setResourceLimits(createContainerOpts.HostConfig, s.getServiceResourceLimits())
+	if err != nil {
+		return nil, err
 	}
+	s.security.apply(createContainerOpts.HostConfig)
 
 	s.Debugln("Creating service container", createContainerOpts.Name, "...")
 	container, err := s.client.CreateContainer(createContainerOpts)
@@ -352,60 +729,81 @@ func (s *DockerExecutor) createService(service, version string) (*docker.Contain
 		return nil, err
 	}
 
+	// service stays on the default network too, where it can be reached with legacy links
+	aliases := getServiceAliases(linkName)
+	if service.Alias != "" {
+		aliases = []string{service.Alias}
+	}
+
+	err = s.connectToNetwork(ctx, container.ID, aliases)
+	if err != nil {
+		s.failures = append(s.failures, container.ID)
+		return nil, err
+	}
+
 	s.Debugln("Starting service container", container.ID, "...")
-	err = s.client.StartContainer(container.ID, nil)
+	err = s.client.StartContainerWithContext(container.ID, nil, ctx)
 	if err != nil {
-		go s.removeContainer(container.ID)
+		s.failures = append(s.failures, container.ID)
 		return nil, err
 	}
 
 	return container, nil
 }
 
-func (s *DockerExecutor) getServiceNames() ([]string, error) {
-	services := s.Config.Docker.Services
+func (s *DockerExecutor) getServices() ([]common.DockerService, error) {
+	services := append([]common.DockerService{}, s.Config.Docker.Services...)
+
+	var internalServices []string
+	for _, service := range s.Config.Docker.Services {
+		internalServices = append(internalServices, service.Name)
+	}
 
 	if servicesOption, ok := s.Build.Options["services"].([]interface{}); ok {
-		for _, service := range servicesOption {
-			serviceName, ok := service.(string)
-			if !ok {
-				s.Errorln("Invalid service name passed:", service)
+		for _, serviceOption := range servicesOption {
+			service, err := common.NewDockerService(serviceOption)
+			if err != nil {
+				s.Errorln("Invalid service passed:", serviceOption, err)
 				return nil, errors.New("invalid service name")
 			}
 
-			err := s.verifyAllowedImage(serviceName, "services", s.Config.Docker.AllowedServices, s.Config.Docker.Services)
+			err = s.verifyAllowedImage(service.Name, "services", s.Config.Docker.AllowedServices, internalServices)
 			if err != nil {
 				return nil, err
 			}
 
-			services = append(services, serviceName)
+			services = append(services, service)
 		}
 	}
 
 	return services, nil
 }
 
-func (s *DockerExecutor) createServices() ([]string, error) {
-	serviceNames, err := s.getServiceNames()
+func (s *DockerExecutor) createServices(ctx context.Context) error {
+	services, err := s.getServices()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	linksMap := make(map[string]*docker.Container)
 
-	for _, serviceDescription := range serviceNames {
-		service, version, linkName := s.splitServiceAndVersion(serviceDescription)
+	for _, service := range services {
+		image, version, linkName := s.splitServiceAndVersion(service.Name)
+		if service.Alias != "" {
+			linkName = service.Alias
+		}
+
 		if linksMap[linkName] != nil {
-			s.Warningln("Service", serviceDescription, "is already created. Ignoring.")
+			s.Warningln("Service", service, "is already created. Ignoring.")
 			continue
 		}
 
-		container, err := s.createService(service, version)
+		container, err := s.createService(ctx, service, image, version, linkName)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		s.Debugln("Created service", serviceDescription, "as", container.ID)
+		s.Debugln("Created service", service, "as", container.ID)
 		linksMap[linkName] = container
 		s.services = append(s.services, container)
 	}
@@ -422,25 +820,14 @@ func (s *DockerExecutor) createServices() ([]string, error) {
 		for _, service := range s.services {
 			wg.Add(1)
 			go func(service *docker.Container) {
-				s.waitForServiceContainer(service, time.Duration(waitForServicesTimeout)*time.Second)
+				s.waitForServiceContainer(ctx, service, time.Duration(waitForServicesTimeout)*time.Second)
 				wg.Done()
 			}(service)
 		}
 		wg.Wait()
 	}
 
-	var links []string
-	for linkName, container := range linksMap {
-		newContainer, err := s.client.InspectContainer(container.ID)
-		if err != nil {
-			continue
-		}
-		if newContainer.State.Running {
-			links = append(links, container.ID+":"+linkName)
-		}
-	}
-
-	return links, nil
+	return nil
 }
 
 func (s *DockerExecutor) connect() (*docker.Client, error) {
@@ -485,19 +872,23 @@ func (s *DockerExecutor) connect() (*docker.Client, error) {
 	}
 }
 
-func (s *DockerExecutor) createBuildContainer(cmd []string) error {
-	hostname := helpers.StringOrDefault(s.Config.Docker.Hostname, s.Build.ProjectUniqueName())
+func (s *DockerExecutor) getHostname() string {
+	return helpers.StringOrDefault(s.Config.Docker.Hostname, s.Build.ProjectUniqueName())
+}
+
+func (s *DockerExecutor) createBuildContainer(ctx context.Context, cmd []string) error {
+	hostname := s.getHostname()
 	containerName := s.Build.ProjectUniqueName()
 
 	// this will fail potentially some builds if there's name collision
-	s.removeContainer(containerName)
+	s.removeContainer(ctx, containerName)
 
 	imageName, err := s.getImageName()
 	if err != nil {
 		return err
 	}
 
-	image, err := s.getDockerImage(imageName)
+	image, err := s.getDockerImage(ctx, imageName)
 	if err != nil {
 		return err
 	}
@@ -521,50 +912,232 @@ func (s *DockerExecutor) createBuildContainer(cmd []string) error {
 			Privileged:    s.Config.Docker.Privileged,
 			RestartPolicy: docker.NeverRestart(),
 			ExtraHosts:    s.Config.Docker.ExtraHosts,
-			Links:         s.Config.Docker.Links,
 		},
+		Context: ctx,
+	}
+
+	err = setResourceLimits(createContainerOptions.HostConfig, s.getBuildResourceLimits())
+	if err != nil {
+		return err
+	}
+	s.security.apply(createContainerOptions.HostConfig)
+
+	s.Debugln("Creating network...")
+	err = s.createNetwork(ctx)
+	if err != nil {
+		return err
+	}
+	createContainerOptions.HostConfig.NetworkMode = s.network.Name
+
+	err = s.connectLinkedContainers(ctx)
+	if err != nil {
+		return err
 	}
 
 	s.Debugln("Creating services...")
-	links, err := s.createServices()
+	err = s.createServices(ctx)
 	if err != nil {
 		return err
 	}
-	createContainerOptions.HostConfig.Links = append(createContainerOptions.HostConfig.Links, links...)
 
 	s.Debugln("Creating cache directories...")
-	binds, volumesFrom, err := s.createVolumes(image)
+	binds, volumesFrom, err := s.createVolumes(ctx, image)
 	if err != nil {
 		return err
 	}
 	createContainerOptions.HostConfig.Binds = binds
 	createContainerOptions.HostConfig.VolumesFrom = volumesFrom
+	s.binds = binds
+	s.volumesFrom = volumesFrom
 
 	s.Debugln("Creating container", createContainerOptions.Name, "...")
 	container, err := s.client.CreateContainer(createContainerOptions)
 	if err != nil {
 		if container != nil {
-			go s.removeContainer(container.ID)
+			s.failures = append(s.failures, container.ID)
 		}
 		return err
 	}
 
 	s.Debugln("Starting container", container.ID, "...")
-	err = s.client.StartContainer(container.ID, nil)
+	err = s.client.StartContainerWithContext(container.ID, nil, ctx)
 	if err != nil {
-		go s.removeContainer(container.ID)
+		s.failures = append(s.failures, container.ID)
 		return err
 	}
 
 	s.buildContainer = container
+	s.buildImage = image
 	return nil
 }
 
-func (s *DockerExecutor) removeContainer(id string) error {
+// runContainerScript passes the script to shell waiting on stdin of container,
+// streams the output to build log and waits for the container to finish
+func (s *DockerExecutor) runContainerScript(ctx context.Context, container *docker.Container, script string) error {
+	attachContainerOptions := docker.AttachToContainerOptions{
+		Container:    container.ID,
+		InputStream:  bytes.NewBufferString(script),
+		OutputStream: s.BuildLog,
+		ErrorStream:  s.BuildLog,
+		Logs:         true,
+		Stream:       true,
+		Stdin:        true,
+		Stdout:       true,
+		Stderr:       true,
+		RawTerminal:  false,
+	}
+
+	finished := make(chan bool)
+	defer close(finished)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Debugln("Killing container", container.ID, "...")
+			s.client.KillContainer(docker.KillContainerOptions{ID: container.ID})
+		case <-finished:
+		}
+	}()
+
+	s.Debugln("Attaching to container", container.ID, "...")
+	err := s.client.AttachToContainer(attachContainerOptions)
+	if err != nil {
+		return err
+	}
+
+	s.Debugln("Waiting for container", container.ID, "...")
+	exitCode, err := s.client.WaitContainerWithContext(container.ID, ctx)
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return s.getContainerExitError(ctx, container.ID, exitCode)
+	}
+	return nil
+}
+
+func (s *DockerExecutor) getHelperImageName() string {
+	if s.Config.Docker.HelperImage != "" {
+		return s.Config.Docker.HelperImage
+	}
+	return dockerHelperImage
+}
+
+// getHelperUser returns user of build image if it can be used in helper image,
+// so the sources are owned by the user running the build
+func getHelperUser(image *docker.Image) string {
+	if image == nil || image.Config == nil {
+		return ""
+	}
+
+	// named users don't exist in helper image
+	for _, id := range strings.Split(image.Config.User, ":") {
+		if _, err := strconv.Atoi(id); err != nil {
+			return ""
+		}
+	}
+	return image.Config.User
+}
+
+// getScriptContainerOptions returns options of container executing a script,
+// that shares the volumes and the network with build container
+func (s *DockerExecutor) getScriptContainerOptions(suffix, containerType string, image *docker.Image) docker.CreateContainerOptions {
+	createContainerOptions := docker.CreateContainerOptions{
+		Name: s.Build.ProjectUniqueName() + "-" + suffix,
+		Config: &docker.Config{
+			Hostname:     s.getHostname(),
+			Image:        image.ID,
+			Tty:          false,
+			AttachStdin:  true,
+			AttachStdout: true,
+			AttachStderr: true,
+			OpenStdin:    true,
+			StdinOnce:    true,
+			Env:          s.ShellScript.Environment,
+			Cmd:          s.ShellScript.GetCommandWithArguments(),
+			Labels:       s.getLabels(containerType),
+		},
+		HostConfig: &docker.HostConfig{
+			RestartPolicy: docker.NeverRestart(),
+			ExtraHosts:    s.Config.Docker.ExtraHosts,
+			Binds:         s.binds,
+			VolumesFrom:   s.volumesFrom,
+		},
+	}
+	if s.network != nil {
+		createContainerOptions.HostConfig.NetworkMode = s.network.Name
+	}
+	return createContainerOptions
+}
+
+func (s *DockerExecutor) runScriptContainer(ctx context.Context, createContainerOptions docker.CreateContainerOptions, script string) error {
+	// this will fail potentially some builds if there's name collision
+	s.removeContainer(ctx, createContainerOptions.Name)
+
+	s.Debugln("Creating container", createContainerOptions.Name, "...")
+	createContainerOptions.Context = ctx
+	container, err := s.client.CreateContainer(createContainerOptions)
+	if err != nil {
+		if container != nil {
+			s.failures = append(s.failures, container.ID)
+		}
+		return err
+	}
+	s.scriptContainers = append(s.scriptContainers, container)
+	defer s.removeContainer(ctx, container.ID)
+
+	s.Debugln("Starting container", container.ID, "...")
+	err = s.client.StartContainerWithContext(container.ID, nil, ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.runContainerScript(ctx, container, script)
+}
+
+// runHelperScript runs script in helper container, so the build image doesn't need git
+func (s *DockerExecutor) runHelperScript(ctx context.Context, suffix, script string) error {
+	helperImage, err := s.getDockerImage(ctx, s.getHelperImageName())
+	if err != nil {
+		return err
+	}
+
+	createContainerOptions := s.getScriptContainerOptions(suffix, "helper", helperImage)
+	createContainerOptions.Config.Cmd = []string{"bash"}
+	createContainerOptions.Config.User = getHelperUser(s.buildImage)
+
+	// the local cache is accessed by helper at the same path as on the host
+	if cache := s.Config.Cache; cache != nil && cache.Type == "local" && cache.Path != "" {
+		cachePath, err := filepath.Abs(cache.Path)
+		if err != nil {
+			return err
+		}
+		binds := append([]string{}, s.binds...)
+		createContainerOptions.HostConfig.Binds = append(binds, cachePath+":"+cachePath)
+	}
+	return s.runScriptContainer(ctx, createContainerOptions, script)
+}
+
+// runBuildImageScript runs script in a new container created from build image
+func (s *DockerExecutor) runBuildImageScript(ctx context.Context, suffix, script string) error {
+	createContainerOptions := s.getScriptContainerOptions(suffix, "build", s.buildImage)
+	createContainerOptions.HostConfig.Privileged = s.Config.Docker.Privileged
+
+	err := setResourceLimits(createContainerOptions.HostConfig, s.getBuildResourceLimits())
+	if err != nil {
+		return err
+	}
+	s.security.apply(createContainerOptions.HostConfig)
+	return s.runScriptContainer(ctx, createContainerOptions, script)
+}
+
+func (s *DockerExecutor) removeContainer(ctx context.Context, id string) error {
 	removeContainerOptions := docker.RemoveContainerOptions{
 		ID:            id,
 		RemoveVolumes: true,
 		Force:         true,
+		Context:       ctx,
 	}
 	err := s.client.RemoveContainer(removeContainerOptions)
 	s.Debugln("Removed container", id, "with", err)
@@ -613,8 +1186,8 @@ func (s *DockerExecutor) getImageName() (string, error) {
 	return s.Config.Docker.Image, nil
 }
 
-func (s *DockerExecutor) Prepare(globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
-	err := s.AbstractExecutor.Prepare(globalConfig, config, build)
+func (s *DockerExecutor) Prepare(ctx context.Context, globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
+	err := s.AbstractExecutor.Prepare(ctx, globalConfig, config, build)
 	if err != nil {
 		return err
 	}
@@ -633,80 +1206,201 @@ func (s *DockerExecutor) Prepare(globalConfig *common.Config, config *common.Run
 	}
 	s.Println("Using Docker executor with image", imageName, "...")
 
-	client, err := docker_helpers.Connect(s.Config.Docker.DockerCredentials, dockerAPIVersion)
+	s.pullPolicy, err = s.getPullPolicy()
 	if err != nil {
 		return err
 	}
-	s.client = client
-	return nil
+	s.pulledImages = getPulledImageCache(s.getImageCacheFile(globalConfig))
+
+	err = setResourceLimits(&docker.HostConfig{}, s.getBuildResourceLimits())
+	if err != nil {
+		return fmt.Errorf("Invalid resource limits of build container: %v", err)
+	}
+
+	err = setResourceLimits(&docker.HostConfig{}, s.getServiceResourceLimits())
+	if err != nil {
+		return fmt.Errorf("Invalid resource limits of service containers: %v", err)
+	}
+
+	s.security, err = s.getSecurityOptions()
+	if err != nil {
+		return err
+	}
+
+	s.client, s.apiVersion, err = docker_helpers.ConnectNegotiated(s.Config.Docker.DockerCredentials,
+		dockerMinAPIVersion, dockerAPIVersion)
+	if err != nil {
+		return err
+	}
+	return s.verifyAPIVersion()
 }
 
-func (s *DockerExecutor) Cleanup() {
+func (s *DockerExecutor) Cleanup(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	remove := func(id string) {
+		wg.Add(1)
+		go func() {
+			s.removeContainer(ctx, id)
+			wg.Done()
+		}()
+	}
+
 	for _, service := range s.services {
-		s.removeContainer(service.ID)
+		remove(service.ID)
 	}
 
 	for _, cache := range s.caches {
-		s.removeContainer(cache.ID)
+		remove(cache.ID)
+	}
+
+	for _, container := range s.scriptContainers {
+		remove(container.ID)
+	}
+
+	for _, id := range s.failures {
+		remove(id)
 	}
 
 	if s.buildContainer != nil {
-		s.removeContainer(s.buildContainer.ID)
+		remove(s.buildContainer.ID)
 		s.buildContainer = nil
 	}
 
-	s.AbstractExecutor.Cleanup()
+	wg.Wait()
+
+	if s.network != nil {
+		s.removeNetwork(ctx, s.network.ID)
+		s.network = nil
+	}
+
+	s.AbstractExecutor.Cleanup(ctx)
 }
 
-func (s *DockerExecutor) runServiceHealthCheckContainer(container *docker.Container, timeout time.Duration) error {
-	waitImage, err := s.getDockerImage("gitlab/gitlab-runner:service")
-	if err != nil {
-		return err
+func getServiceName(container *docker.Container) string {
+	return strings.TrimPrefix(container.Name, "/")
+}
+
+func getExposedTCPPorts(container *docker.Container) (ports []string) {
+	if container.Config == nil {
+		return
 	}
 
-	waitContainerOpts := docker.CreateContainerOptions{
-		Name: container.Name + "-wait-for-service",
-		Config: &docker.Config{
-			Image:  waitImage.ID,
-			Labels: s.getLabels("wait", "wait="+container.ID),
-		},
-		HostConfig: &docker.HostConfig{
-			RestartPolicy: docker.NeverRestart(),
-			Links:         []string{container.Name + ":" + container.Name},
-		},
+	for port := range container.Config.ExposedPorts {
+		if port.Proto() == "tcp" {
+			ports = append(ports, port.Port())
+		}
 	}
-	s.Debugln("Waiting for service container", container.Name, "to be up and running...")
-	waitContainer, err := s.client.CreateContainer(waitContainerOpts)
-	if err != nil {
-		return err
+	sort.Strings(ports)
+	return
+}
+
+func (s *DockerExecutor) getServiceAddress(container *docker.Container) string {
+	if container.NetworkSettings == nil {
+		return ""
 	}
-	defer s.removeContainer(waitContainer.ID)
-	err = s.client.StartContainer(waitContainer.ID, nil)
-	if err != nil {
-		return err
+
+	if s.network != nil {
+		if network, ok := container.NetworkSettings.Networks[s.network.Name]; ok && network.IPAddress != "" {
+			return network.IPAddress
+		}
 	}
+	return container.NetworkSettings.IPAddress
+}
 
-	waitResult := make(chan error, 1)
-	go func() {
-		statusCode, err := s.client.WaitContainer(waitContainer.ID)
-		if err == nil && statusCode != 0 {
-			err = fmt.Errorf("Status code: %d", statusCode)
+func (s *DockerExecutor) waitForServiceHealthy(ctx context.Context, id string) error {
+	for {
+		container, err := s.client.InspectContainerWithContext(id, ctx)
+		if err != nil {
+			return err
 		}
-		waitResult <- err
-	}()
 
-	// these are warnings and they don't make the build fail
-	select {
-	case err := <-waitResult:
-		return err
-	case <-time.After(timeout):
-		return fmt.Errorf("didn't respond in timely maner: %v (consider modifying wait_for_services_timeout).", container.Name, timeout)
+		if !container.State.Running {
+			return fmt.Errorf("%s exited with code %d", getServiceName(container), container.State.ExitCode)
+		}
+
+		switch health := container.State.Health; health.Status {
+		case "healthy":
+			s.Println("Service", getServiceName(container), "is healthy")
+			return nil
+
+		case "unhealthy":
+			message := fmt.Sprintf("%s is unhealthy", getServiceName(container))
+			if len(health.Log) > 0 {
+				message += ": " + strings.TrimSpace(health.Log[len(health.Log)-1].Output)
+			}
+			return errors.New(message)
+		}
+
+		select {
+		case <-time.After(dockerServiceProbeInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("%s didn't become healthy in timely maner", getServiceName(container))
+		}
 	}
+}
+
+func (s *DockerExecutor) waitForServicePorts(ctx context.Context, container *docker.Container) error {
+	ports := getExposedTCPPorts(container)
+	if len(ports) == 0 {
+		s.Println("Service", getServiceName(container), "doesn't expose any TCP ports, not waiting for it")
+		return nil
+	}
+
+	address := s.getServiceAddress(container)
+	if address == "" {
+		return fmt.Errorf("%s doesn't have IP address", getServiceName(container))
+	}
+
+	dialer := net.Dialer{Timeout: dockerServiceProbeInterval}
+	for _, port := range ports {
+		for {
+			conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, port))
+			if err == nil {
+				conn.Close()
+				break
+			}
+
+			select {
+			case <-time.After(dockerServiceProbeInterval):
+			case <-ctx.Done():
+				return fmt.Errorf("%s didn't respond on port %s in timely maner: %v", getServiceName(container), port, err)
+			}
+		}
+	}
+
+	s.Println("Service", getServiceName(container), "is listening on port", strings.Join(ports, ", "))
 	return nil
 }
 
-func (s *DockerExecutor) waitForServiceContainer(container *docker.Container, timeout time.Duration) error {
-	err := s.runServiceHealthCheckContainer(container, timeout)
+// runServiceHealthCheck uses the HEALTHCHECK of service image when it's defined,
+// otherwise it waits until all exposed TCP ports of service accept connections
+func (s *DockerExecutor) runServiceHealthCheck(ctx context.Context, container *docker.Container, timeout time.Duration) error {
+	container, err := s.client.InspectContainerWithContext(container.ID, ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	s.Debugln("Waiting for service container", getServiceName(container), "to be up and running...")
+	if !container.State.Running {
+		return fmt.Errorf("%s exited with code %d", getServiceName(container), container.State.ExitCode)
+	} else if container.State.Health.Status != "" {
+		err = s.waitForServiceHealthy(ctx, container.ID)
+	} else {
+		err = s.waitForServicePorts(ctx, container)
+	}
+
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%v (consider modifying wait_for_services_timeout).", err)
+	}
+	return err
+}
+
+func (s *DockerExecutor) waitForServiceContainer(ctx context.Context, container *docker.Container, timeout time.Duration) error {
+	err := s.runServiceHealthCheck(ctx, container, timeout)
 	if err == nil {
 		return nil
 	}
@@ -726,6 +1420,7 @@ func (s *DockerExecutor) waitForServiceContainer(container *docker.Container, ti
 		Stdout:       true,
 		Stderr:       true,
 		Timestamps:   true,
+		Context:      ctx,
 	})
 	if err == nil {
 		if containerLog := containerBuffer.String(); containerLog != "" {
@@ -1,10 +1,8 @@
 package docker
 
 import (
-	"bytes"
-	"fmt"
-
-	"github.com/fsouza/go-dockerclient"
+	"context"
+	"strings"
 
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors"
@@ -14,51 +12,44 @@ type DockerCommandExecutor struct {
 	DockerExecutor
 }
 
-func (s *DockerCommandExecutor) Start() error {
+func (s *DockerCommandExecutor) Start(ctx context.Context) error {
 	s.Debugln("Starting Docker command...")
 
 	// Create container
-	err := s.createBuildContainer(s.ShellScript.GetCommandWithArguments())
+	err := s.createBuildContainer(ctx, s.ShellScript.GetCommandWithArguments())
 	if err != nil {
 		return err
 	}
 
-	// Wait for process to exit
-	go func() {
-		attachContainerOptions := docker.AttachToContainerOptions{
-			Container:    s.buildContainer.ID,
-			InputStream:  bytes.NewBufferString(s.ShellScript.Script),
-			OutputStream: s.BuildLog,
-			ErrorStream:  s.BuildLog,
-			Logs:         true,
-			Stream:       true,
-			Stdin:        true,
-			Stdout:       true,
-			Stderr:       true,
-			RawTerminal:  false,
-		}
+	// helper image has git installed already
+	delete(s.ShellScript.Stages, common.ShellPrepare)
 
-		s.Debugln("Attaching to container...")
-		err := s.client.AttachToContainer(attachContainerOptions)
-		if err != nil {
-			s.BuildFinish <- err
-			return
-		}
+	s.StartStages(ctx, s.runStage)
+	return nil
+}
 
-		s.Debugln("Waiting for container...")
-		exitCode, err := s.client.WaitContainer(s.buildContainer.ID)
-		if err != nil {
-			s.BuildFinish <- err
-			return
-		}
+func (s *DockerCommandExecutor) runStage(ctx context.Context, stage common.ShellScriptStage) error {
+	script, _ := s.ShellScript.GetStageScript(stage)
 
-		if exitCode == 0 {
-			s.BuildFinish <- nil
-		} else {
-			s.BuildFinish <- fmt.Errorf("exit code %d", exitCode)
-		}
-	}()
-	return nil
+	switch stage {
+	case common.ShellGetSources:
+		return s.runHelperScript(ctx, "helper", script)
+
+	case common.ShellRestoreCache, common.ShellArchiveCache:
+		return s.runHelperScript(ctx, strings.Replace(string(stage), "_", "-", -1), script)
+
+	case common.ShellDownloadArtifacts:
+		return s.runHelperScript(ctx, "downloader", script)
+
+	case common.ShellUploadArtifacts:
+		return s.runHelperScript(ctx, "uploader", script)
+
+	case common.ShellUserScript:
+		return s.runContainerScript(ctx, s.buildContainer, script)
+
+	default:
+		return s.runBuildImageScript(ctx, strings.Replace(string(stage), "_", "-", -1), script)
+	}
 }
 
 func init() {
@@ -70,7 +61,7 @@ func init() {
 			Type:  common.NormalShell,
 		},
 		ShowHostname:     true,
-		SupportedOptions: []string{"image", "services"},
+		SupportedOptions: dockerSupportedOptions,
 	}
 
 	create := func() common.Executor {
@@ -1,7 +1,9 @@
 package docker
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors"
@@ -13,7 +15,7 @@ type DockerSSHExecutor struct {
 	sshCommand ssh.Command
 }
 
-func (s *DockerSSHExecutor) Start() error {
+func (s *DockerSSHExecutor) Start(ctx context.Context) error {
 	if s.Config.SSH == nil {
 		return errors.New("Missing SSH configuration")
 	}
@@ -21,12 +23,12 @@ func (s *DockerSSHExecutor) Start() error {
 	s.Debugln("Starting SSH command...")
 
 	// Create container
-	err := s.createBuildContainer([]string{})
+	err := s.createBuildContainer(ctx, []string{})
 	if err != nil {
 		return err
 	}
 
-	containerData, err := s.client.InspectContainer(s.buildContainer.ID)
+	containerData, err := s.client.InspectContainerWithContext(s.buildContainer.ID, ctx)
 	if err != nil {
 		return err
 	}
@@ -36,31 +38,35 @@ func (s *DockerSSHExecutor) Start() error {
 		Config:      *s.Config.SSH,
 		Environment: s.ShellScript.Environment,
 		Command:     s.ShellScript.GetFullCommand(),
-		Stdin:       s.ShellScript.GetScriptBytes(),
 		Stdout:      s.BuildLog,
 		Stderr:      s.BuildLog,
 	}
-	s.sshCommand.Host = &containerData.NetworkSettings.IPAddress
+	ipAddress := containerData.NetworkSettings.Networks[s.network.Name].IPAddress
+	s.sshCommand.Host = &ipAddress
 
 	s.Debugln("Connecting to SSH server...")
-	err = s.sshCommand.Connect()
+	err = s.sshCommand.Connect(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Wait for process to exit
-	go func() {
-		s.Debugln("Will run SSH command...")
-		err := s.sshCommand.Run()
-		s.Debugln("SSH command finished with", err)
-		s.BuildFinish <- err
-	}()
+	s.StartStages(ctx, s.runStage)
 	return nil
 }
 
-func (s *DockerSSHExecutor) Cleanup() {
+func (s *DockerSSHExecutor) runStage(ctx context.Context, stage common.ShellScriptStage) error {
+	s.sshCommand.Stdin = s.ShellScript.GetStageScriptBytes(stage)
+	err := s.sshCommand.Run(ctx)
+	s.Debugln("SSH command finished with", err)
+	if err != nil && s.isOOMKilled(ctx, s.buildContainer.ID) {
+		err = fmt.Errorf("%v: %s", err, oomKilledMessage)
+	}
+	return err
+}
+
+func (s *DockerSSHExecutor) Cleanup(ctx context.Context) {
 	s.sshCommand.Cleanup()
-	s.DockerExecutor.Cleanup()
+	s.DockerExecutor.Cleanup(ctx)
 }
 
 func init() {
@@ -72,7 +78,7 @@ func init() {
 			Type:  common.LoginShell,
 		},
 		ShowHostname:     true,
-		SupportedOptions: []string{"image", "services"},
+		SupportedOptions: dockerSupportedOptions,
 	}
 
 	create := func() common.Executor {
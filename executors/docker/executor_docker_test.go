@@ -0,0 +1,319 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fsouza/go-dockerclient"
+	dtesting "github.com/fsouza/go-dockerclient/testing"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+func newPullPolicyExecutor(pullPolicy string, allowedPullPolicies []string, option interface{}) *DockerExecutor {
+	executor := &DockerExecutor{}
+	executor.Config = &common.RunnerConfig{
+		Docker: &common.DockerConfig{
+			PullPolicy:          pullPolicy,
+			AllowedPullPolicies: allowedPullPolicies,
+		},
+	}
+	executor.Build = &common.Build{
+		GetBuildResponse: common.GetBuildResponse{
+			Options: common.BuildOptions{
+				"pull_policy": option,
+			},
+		},
+		Runner: executor.Config,
+	}
+	return executor
+}
+
+func TestPullPolicyFromConfig(t *testing.T) {
+	executor := newPullPolicyExecutor(PullPolicyNever, nil, nil)
+	pullPolicy, err := executor.getPullPolicy()
+	assert.NoError(t, err)
+	assert.Equal(t, PullPolicyNever, pullPolicy)
+}
+
+func TestPullPolicyDefault(t *testing.T) {
+	executor := newPullPolicyExecutor("", nil, nil)
+	pullPolicy, err := executor.getPullPolicy()
+	assert.NoError(t, err)
+	assert.Equal(t, "", pullPolicy)
+}
+
+func TestPullPolicyInvalid(t *testing.T) {
+	executor := newPullPolicyExecutor("sometimes", nil, nil)
+	_, err := executor.getPullPolicy()
+	assert.Error(t, err)
+}
+
+func TestPullPolicyFromAllowedOption(t *testing.T) {
+	executor := newPullPolicyExecutor(PullPolicyIfNotPresent, []string{PullPolicyAlways}, PullPolicyAlways)
+	pullPolicy, err := executor.getPullPolicy()
+	assert.NoError(t, err)
+	assert.Equal(t, PullPolicyAlways, pullPolicy)
+}
+
+func TestPullPolicyFromNotAllowedOption(t *testing.T) {
+	executor := newPullPolicyExecutor(PullPolicyAlways, []string{PullPolicyAlways}, PullPolicyNever)
+	_, err := executor.getPullPolicy()
+	assert.Error(t, err)
+
+	executor = newPullPolicyExecutor(PullPolicyAlways, nil, PullPolicyNever)
+	_, err = executor.getPullPolicy()
+	assert.Error(t, err)
+}
+
+func TestServiceAliases(t *testing.T) {
+	assert.Equal(t, []string{"mysql"}, getServiceAliases("mysql"))
+	assert.Equal(t, []string{"tutum__wordpress", "tutum-wordpress"}, getServiceAliases("tutum__wordpress"))
+}
+
+func TestHasVolume(t *testing.T) {
+	assert.True(t, hasVolume(&docker.Container{Volumes: map[string]string{"/cache": "/var/lib/docker/vfs"}}, "/cache"))
+	assert.True(t, hasVolume(&docker.Container{Mounts: []docker.Mount{{Destination: "/cache"}}}, "/cache"))
+	assert.False(t, hasVolume(&docker.Container{Mounts: []docker.Mount{{Destination: "/other"}}}, "/cache"))
+}
+
+func TestSetResourceLimits(t *testing.T) {
+	hostConfig := &docker.HostConfig{}
+	err := setResourceLimits(hostConfig, resourceLimits{
+		memory:     "512m",
+		memorySwap: "1g",
+		cpus:       "1.5",
+		cpusetCPUs: "0-1",
+		pidsLimit:  100,
+		shmSize:    "64m",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(512*1024*1024), hostConfig.Memory)
+	assert.Equal(t, int64(1024*1024*1024), hostConfig.MemorySwap)
+	assert.Equal(t, int64(100000), hostConfig.CPUPeriod)
+	assert.Equal(t, int64(150000), hostConfig.CPUQuota)
+	assert.Equal(t, "0-1", hostConfig.CPUSetCPUs)
+	assert.Equal(t, int64(100), hostConfig.PidsLimit)
+	assert.Equal(t, int64(64*1024*1024), hostConfig.ShmSize)
+
+	hostConfig = &docker.HostConfig{}
+	err = setResourceLimits(hostConfig, resourceLimits{memorySwap: "-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), hostConfig.MemorySwap)
+	assert.Equal(t, int64(0), hostConfig.Memory)
+	assert.Equal(t, int64(0), hostConfig.CPUQuota)
+}
+
+func TestSetInvalidResourceLimits(t *testing.T) {
+	assert.Error(t, setResourceLimits(&docker.HostConfig{}, resourceLimits{memory: "lots"}))
+	assert.Error(t, setResourceLimits(&docker.HostConfig{}, resourceLimits{cpus: "-1"}))
+	assert.Error(t, setResourceLimits(&docker.HostConfig{}, resourceLimits{shmSize: "1x"}))
+}
+
+func TestContainerExitError(t *testing.T) {
+	server, err := dtesting.NewServer("127.0.0.1:0", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	server.CustomHandler("/containers/oom/json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(docker.Container{ID: "oom", State: docker.State{ExitCode: 137, OOMKilled: true}})
+	}))
+	server.CustomHandler("/containers/failed/json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(docker.Container{ID: "failed", State: docker.State{ExitCode: 1}})
+	}))
+
+	executor := &DockerExecutor{}
+	executor.client, err = docker.NewClient(server.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.EqualError(t, executor.getContainerExitError(context.Background(), "oom", 137), "exit code 137: "+oomKilledMessage)
+	assert.EqualError(t, executor.getContainerExitError(context.Background(), "failed", 1), "exit code 1")
+}
+
+func TestAuthConfigFromVariable(t *testing.T) {
+	executor := &DockerExecutor{}
+	executor.Config = &common.RunnerConfig{
+		Environment: []string{`DOCKER_AUTH_CONFIG={"auths": {"registry.example.com": {"auth": "cnVubmVyOnBhc3N3b3Jk"}}}`},
+		Docker:      &common.DockerConfig{},
+	}
+	executor.Build = &common.Build{
+		GetBuildResponse: common.GetBuildResponse{
+			Variables: []common.BuildVariable{
+				{Key: "DOCKER_AUTH_CONFIG", Value: `{"auths": {"registry.example.com": {"auth": "YnVpbGQ6cGFzc3dvcmQ="}}}`},
+			},
+		},
+		Runner: executor.Config,
+	}
+
+	authConfig, err := executor.getAuthConfig("registry.example.com/group/image:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, "build", authConfig.Username)
+	assert.Equal(t, "password", authConfig.Password)
+
+	executor.Build.Variables = nil
+	authConfig, err = executor.getAuthConfig("registry.example.com/group/image:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, "runner", authConfig.Username)
+}
+
+func newServiceHealthCheckExecutor(t *testing.T, containers ...*docker.Container) (*dtesting.DockerServer, *DockerExecutor) {
+	server, err := dtesting.NewServer("127.0.0.1:0", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, container := range containers {
+		container := container
+		server.CustomHandler("^/containers/"+container.ID+"/json$", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(container)
+			// service becomes healthy after first check
+			if container.State.Health.Status == "starting" {
+				container.State.Health.Status = "healthy"
+			}
+		}))
+	}
+
+	executor := &DockerExecutor{}
+	executor.Config = &common.RunnerConfig{}
+	executor.Build = &common.Build{Runner: executor.Config}
+	executor.client, err = docker.NewClient(server.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, executor
+}
+
+func TestServiceHealthCheck(t *testing.T) {
+	healthy := &docker.Container{
+		ID:    "healthy",
+		Name:  "/service-healthy",
+		State: docker.State{Running: true, Health: docker.Health{Status: "starting"}},
+	}
+	unhealthy := &docker.Container{
+		ID:   "unhealthy",
+		Name: "/service-unhealthy",
+		State: docker.State{Running: true, Health: docker.Health{
+			Status: "unhealthy",
+			Log:    []docker.HealthCheck{{ExitCode: 1, Output: "connection refused\n"}},
+		}},
+	}
+	exited := &docker.Container{
+		ID:    "exited",
+		Name:  "/service-exited",
+		State: docker.State{ExitCode: 2},
+	}
+
+	server, executor := newServiceHealthCheckExecutor(t, healthy, unhealthy, exited)
+	defer server.Stop()
+
+	assert.NoError(t, executor.runServiceHealthCheck(context.Background(), healthy, time.Minute))
+	assert.EqualError(t, executor.runServiceHealthCheck(context.Background(), unhealthy, time.Minute), "service-unhealthy is unhealthy: connection refused")
+	assert.EqualError(t, executor.runServiceHealthCheck(context.Background(), exited, time.Minute), "service-exited exited with code 2")
+}
+
+func TestServicePortsProbe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	listening := &docker.Container{
+		ID:              "listening",
+		Name:            "/service-listening",
+		State:           docker.State{Running: true},
+		Config:          &docker.Config{ExposedPorts: map[docker.Port]struct{}{docker.Port(port + "/tcp"): {}, "53/udp": {}}},
+		NetworkSettings: &docker.NetworkSettings{IPAddress: "127.0.0.1"},
+	}
+	noPorts := &docker.Container{
+		ID:              "no-ports",
+		Name:            "/service-no-ports",
+		State:           docker.State{Running: true},
+		Config:          &docker.Config{},
+		NetworkSettings: &docker.NetworkSettings{IPAddress: "127.0.0.1"},
+	}
+
+	server, executor := newServiceHealthCheckExecutor(t, listening, noPorts)
+	defer server.Stop()
+
+	assert.NoError(t, executor.runServiceHealthCheck(context.Background(), listening, time.Minute))
+	assert.NoError(t, executor.runServiceHealthCheck(context.Background(), noPorts, time.Minute))
+
+	listener.Close()
+	err = executor.runServiceHealthCheck(context.Background(), listening, 0)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "didn't respond on port "+port)
+		assert.Contains(t, err.Error(), "wait_for_services_timeout")
+	}
+
+	// the aborted build doesn't wait for services
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = executor.runServiceHealthCheck(ctx, listening, time.Minute)
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "wait_for_services_timeout")
+	}
+}
+
+func TestExposedTCPPorts(t *testing.T) {
+	container := &docker.Container{
+		Config: &docker.Config{ExposedPorts: map[docker.Port]struct{}{"5432/tcp": {}, "53/udp": {}, "3306/tcp": {}}},
+	}
+	assert.Equal(t, []string{"3306", "5432"}, getExposedTCPPorts(container))
+}
+
+func TestHelperImageName(t *testing.T) {
+	executor := &DockerExecutor{}
+	executor.Config = &common.RunnerConfig{
+		Docker: &common.DockerConfig{},
+	}
+	assert.Equal(t, dockerHelperImage, executor.getHelperImageName())
+
+	executor.Config.Docker.HelperImage = "my/helper"
+	assert.Equal(t, "my/helper", executor.getHelperImageName())
+}
+
+func TestHelperUser(t *testing.T) {
+	userImage := func(user string) *docker.Image {
+		return &docker.Image{Config: &docker.Config{User: user}}
+	}
+
+	assert.Equal(t, "", getHelperUser(nil))
+	assert.Equal(t, "", getHelperUser(userImage("")))
+	assert.Equal(t, "1000", getHelperUser(userImage("1000")))
+	assert.Equal(t, "1000:100", getHelperUser(userImage("1000:100")))
+	assert.Equal(t, "", getHelperUser(userImage("node")))
+	assert.Equal(t, "", getHelperUser(userImage("1000:users")))
+}
+
+func TestVerifyAPIVersion(t *testing.T) {
+	executor := &DockerExecutor{}
+	executor.Config = &common.RunnerConfig{
+		Docker: &common.DockerConfig{},
+	}
+	executor.security = &securityOptions{}
+	executor.apiVersion, _ = docker.NewAPIVersion("1.22")
+	assert.NoError(t, executor.verifyAPIVersion())
+
+	executor.Config.Docker.PidsLimit = 100
+	assert.Error(t, executor.verifyAPIVersion())
+
+	executor.apiVersion, _ = docker.NewAPIVersion("1.23")
+	assert.NoError(t, executor.verifyAPIVersion())
+
+	executor.security.sysctls = map[string]string{"net.ipv4.ip_forward": "1"}
+	assert.Error(t, executor.verifyAPIVersion())
+
+	executor.apiVersion, _ = docker.NewAPIVersion("1.24")
+	assert.NoError(t, executor.verifyAPIVersion())
+}
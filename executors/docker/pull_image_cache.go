@@ -1,6 +1,10 @@
 package docker
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 )
@@ -13,10 +17,97 @@ type PulledImage struct {
 
 type PulledImageCache struct {
 	images map[string]PulledImage
+	file   string
 	lock   sync.RWMutex
 }
 
-var pulledImageCache PulledImageCache
+// caches are shared by all runners using the same cache file,
+// the one without file is kept only in memory
+var pulledImageCaches = make(map[string]*PulledImageCache)
+var pulledImageCachesLock sync.Mutex
+
+func getPulledImageCache(file string) *PulledImageCache {
+	pulledImageCachesLock.Lock()
+	defer pulledImageCachesLock.Unlock()
+
+	cache := pulledImageCaches[file]
+	if cache == nil {
+		cache = &PulledImageCache{file: file}
+		cache.load()
+		pulledImageCaches[file] = cache
+	}
+	return cache
+}
+
+// images are pulled per Docker daemon, so the same image used
+// by runners connecting to different daemons has to be tracked separately
+func pulledImageKey(endpoint, imageName string) string {
+	return endpoint + "|" + imageName
+}
+
+func (c *PulledImageCache) load() error {
+	if c.file == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(c.file)
+	if err != nil {
+		return err
+	}
+
+	var images map[string]PulledImage
+	err = json.Unmarshal(data, &images)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.images = images
+	return nil
+}
+
+func (c *PulledImageCache) save() error {
+	if c.file == "" {
+		return nil
+	}
+
+	// don't store expired images
+	images := make(map[string]PulledImage)
+	for imageName, image := range c.images {
+		if !image.isExpired() {
+			images[imageName] = image
+		}
+	}
+
+	data, err := json.Marshal(images)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(c.file), 0700)
+
+	// write to temporary file first to not leave partially written cache
+	tempFile := c.file + ".tmp"
+	err = ioutil.WriteFile(tempFile, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tempFile, c.file)
+}
+
+func (i PulledImage) isExpired() bool {
+	currentTime := time.Now()
+	// ct < lp: lp is in future
+	if currentTime.Before(i.LastPulled) {
+		return true
+	}
+	// ct > lp + ttl: image expired
+	if currentTime.After(i.LastPulled.Add(i.TTL)) {
+		return true
+	}
+	return false
+}
 
 func (c *PulledImageCache) isExpired(imageName string) bool {
 	c.lock.RLock()
@@ -25,21 +116,12 @@ func (c *PulledImageCache) isExpired(imageName string) bool {
 		return true
 	}
 	if image, ok := c.images[imageName]; ok {
-		currentTime := time.Now()
-		// ct < lp: lp is in future
-		if currentTime.Before(image.LastPulled) {
-			return true
-		}
-		// ct > lp + ttl: image expired
-		if currentTime.After(image.LastPulled.Add(image.TTL)) {
-			return true
-		}
-		return false
+		return image.isExpired()
 	}
 	return true
 }
 
-func (c *PulledImageCache) mark(imageName string, id string, ttl time.Duration) {
+func (c *PulledImageCache) mark(imageName string, id string, ttl time.Duration) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	if c.images == nil {
@@ -50,4 +132,5 @@ func (c *PulledImageCache) mark(imageName string, id string, ttl time.Duration)
 		Id:         id,
 		TTL:        ttl,
 	}
+	return c.save()
 }
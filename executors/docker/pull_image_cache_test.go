@@ -1,6 +1,9 @@
 package docker
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"github.com/stretchr/testify/assert"
 	"time"
@@ -37,3 +40,35 @@ func TestImagePulledInTheFuture(t *testing.T) {
 	result := cache.isExpired("test")
 	assert.Equal(t, true, result)
 }
+
+func TestImageKeyedByEndpoint(t *testing.T) {
+	cache := PulledImageCache{}
+	cache.mark(pulledImageKey("unix:///var/run/docker.sock", "test"), "id", time.Minute)
+	assert.False(t, cache.isExpired(pulledImageKey("unix:///var/run/docker.sock", "test")))
+	assert.True(t, cache.isExpired(pulledImageKey("tcp://docker:2375", "test")))
+}
+
+func TestPersistedImageCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulled-images")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "images.json")
+	cache := &PulledImageCache{file: file}
+	assert.NoError(t, cache.mark("test", "id", time.Minute))
+	assert.NoError(t, cache.mark("expired", "id", 0))
+
+	loadedCache := &PulledImageCache{file: file}
+	assert.NoError(t, loadedCache.load())
+	assert.False(t, loadedCache.isExpired("test"))
+	assert.Equal(t, "id", loadedCache.images["test"].Id)
+	_, found := loadedCache.images["expired"]
+	assert.False(t, found)
+}
+
+func TestSharedImageCache(t *testing.T) {
+	assert.True(t, getPulledImageCache("") == getPulledImageCache(""))
+	assert.False(t, getPulledImageCache("") == getPulledImageCache("/non-existing/images.json"))
+}
@@ -0,0 +1,247 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsouza/go-dockerclient"
+)
+
+// tmpfsAllowedOptions are the tmpfs mount options that can be requested by the build,
+// the options like exec, suid or dev would weaken the defaults set by Docker
+var tmpfsAllowedOptions = []string{"ro", "rw", "noexec", "nosuid", "nodev", "size=*", "mode=*", "uid=*", "gid=*", "nr_inodes=*"}
+
+type securityOptions struct {
+	capAdd      []string
+	capDrop     []string
+	securityOpt []string
+	devices     []string
+	tmpfs       map[string]string
+	sysctls     map[string]string
+	usernsMode  string
+	readOnly    bool
+}
+
+func isAllowedValue(value string, allowedValues []string) bool {
+	for _, allowedValue := range allowedValues {
+		if ok, _ := filepath.Match(allowedValue, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func copyStringMap(values map[string]string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range values {
+		result[key] = value
+	}
+	return result
+}
+
+func parseDevice(device string) (docker.Device, error) {
+	parts := strings.Split(device, ":")
+	result := docker.Device{
+		PathOnHost:        parts[0],
+		PathInContainer:   parts[0],
+		CgroupPermissions: "rwm",
+	}
+
+	switch len(parts) {
+	case 3:
+		result.CgroupPermissions = parts[2]
+		fallthrough
+	case 2:
+		result.PathInContainer = parts[1]
+	case 1:
+	default:
+		return result, fmt.Errorf("invalid device: %s", device)
+	}
+
+	if !filepath.IsAbs(result.PathOnHost) || !filepath.IsAbs(result.PathInContainer) {
+		return result, fmt.Errorf("invalid device: %s", device)
+	}
+	return result, nil
+}
+
+func (s *DockerExecutor) verifyAllowedOption(value, optionName string, allowedValues []string) error {
+	if isAllowedValue(value, allowedValues) {
+		return nil
+	}
+
+	s.Errorln("The", value, "is not present on list of allowed", optionName)
+	for _, allowedValue := range allowedValues {
+		s.Println("-", allowedValue)
+	}
+	s.Println()
+	return errors.New("invalid " + optionName)
+}
+
+func (s *DockerExecutor) getStringsOption(name string) ([]string, error) {
+	value, ok := s.Build.Options[name]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid %s option: %v", name, value)
+	}
+
+	var result []string
+	for _, item := range items {
+		text, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s option: %v", name, item)
+		}
+		result = append(result, text)
+	}
+	return result, nil
+}
+
+func (s *DockerExecutor) getMapOption(name string) (map[string]string, error) {
+	value, ok := s.Build.Options[name]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	items, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid %s option: %v", name, value)
+	}
+
+	result := make(map[string]string)
+	for key, item := range items {
+		result[key] = fmt.Sprint(item)
+	}
+	return result, nil
+}
+
+func (s *DockerExecutor) addAllowedStrings(values *[]string, optionName string, allowedValues []string) error {
+	options, err := s.getStringsOption(optionName)
+	if err != nil {
+		return err
+	}
+
+	for _, option := range options {
+		err = s.verifyAllowedOption(option, optionName, allowedValues)
+		if err != nil {
+			return err
+		}
+		*values = append(*values, option)
+	}
+	return nil
+}
+
+func (s *DockerExecutor) addAllowedMap(values map[string]string, optionName string, allowedKeys []string, allowedValues []string) error {
+	options, err := s.getMapOption(optionName)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range options {
+		err = s.verifyAllowedOption(key, optionName, allowedKeys)
+		if err != nil {
+			return err
+		}
+
+		if allowedValues != nil {
+			for _, item := range strings.Split(value, ",") {
+				err = s.verifyAllowedOption(item, optionName+" options", allowedValues)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		values[key] = value
+	}
+	return nil
+}
+
+// getSecurityOptions merges the configured options with the ones requested by the build,
+// the options that can weaken isolation of containers are accepted only when whitelisted
+func (s *DockerExecutor) getSecurityOptions() (*securityOptions, error) {
+	config := s.Config.Docker
+	options := &securityOptions{
+		capAdd:      append([]string{}, config.CapAdd...),
+		capDrop:     append([]string{}, config.CapDrop...),
+		securityOpt: append([]string{}, config.SecurityOpt...),
+		devices:     append([]string{}, config.Devices...),
+		tmpfs:       copyStringMap(config.Tmpfs),
+		sysctls:     copyStringMap(config.Sysctls),
+		usernsMode:  config.UsernsMode,
+		readOnly:    config.ReadOnly,
+	}
+
+	err := s.addAllowedStrings(&options.capAdd, "cap_add", config.AllowedCapAdd)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.addAllowedStrings(&options.securityOpt, "security_opt", config.AllowedSecurityOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.addAllowedStrings(&options.devices, "devices", config.AllowedDevices)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.addAllowedMap(options.tmpfs, "tmpfs", config.AllowedTmpfs, tmpfsAllowedOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.addAllowedMap(options.sysctls, "sysctls", config.AllowedSysctls, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// dropping capabilities only restricts the container
+	capDrop, err := s.getStringsOption("cap_drop")
+	if err != nil {
+		return nil, err
+	}
+	options.capDrop = append(options.capDrop, capDrop...)
+
+	if usernsMode, ok := s.Build.Options["userns_mode"].(string); ok && usernsMode != "" {
+		err = s.verifyAllowedOption(usernsMode, "userns_mode", config.AllowedUsernsModes)
+		if err != nil {
+			return nil, err
+		}
+		options.usernsMode = usernsMode
+	}
+
+	// read only root filesystem can be enabled, but not disabled by the build
+	if readOnly, ok := s.Build.Options["read_only"].(bool); ok && readOnly {
+		options.readOnly = true
+	}
+
+	for _, device := range options.devices {
+		_, err = parseDevice(device)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+func (o *securityOptions) apply(hostConfig *docker.HostConfig) {
+	hostConfig.CapAdd = o.capAdd
+	hostConfig.CapDrop = o.capDrop
+	hostConfig.SecurityOpt = o.securityOpt
+	hostConfig.Tmpfs = o.tmpfs
+	hostConfig.Sysctls = o.sysctls
+	hostConfig.UsernsMode = o.usernsMode
+	hostConfig.ReadonlyRootfs = o.readOnly
+
+	hostConfig.Devices = nil
+	for _, device := range o.devices {
+		// devices are verified when the options are created
+		parsedDevice, _ := parseDevice(device)
+		hostConfig.Devices = append(hostConfig.Devices, parsedDevice)
+	}
+}
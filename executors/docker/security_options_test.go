@@ -0,0 +1,119 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/fsouza/go-dockerclient"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+func newSecurityExecutor(config *common.DockerConfig, options common.BuildOptions) *DockerExecutor {
+	executor := &DockerExecutor{}
+	executor.Config = &common.RunnerConfig{
+		Docker: config,
+	}
+	executor.Build = &common.Build{
+		GetBuildResponse: common.GetBuildResponse{
+			Options: options,
+		},
+		Runner: executor.Config,
+	}
+	return executor
+}
+
+func TestSecurityOptionsFromConfig(t *testing.T) {
+	executor := newSecurityExecutor(&common.DockerConfig{
+		CapAdd:      []string{"NET_ADMIN"},
+		CapDrop:     []string{"ALL"},
+		SecurityOpt: []string{"seccomp=/etc/docker/seccomp.json"},
+		Devices:     []string{"/dev/fuse", "/dev/sda:/dev/xvda:r"},
+		Tmpfs:       map[string]string{"/tmp": "rw,size=64m"},
+		Sysctls:     map[string]string{"net.ipv4.ip_forward": "1"},
+		UsernsMode:  "host",
+		ReadOnly:    true,
+	}, nil)
+
+	options, err := executor.getSecurityOptions()
+	assert.NoError(t, err)
+
+	hostConfig := &docker.HostConfig{}
+	options.apply(hostConfig)
+	assert.Equal(t, []string{"NET_ADMIN"}, hostConfig.CapAdd)
+	assert.Equal(t, []string{"ALL"}, hostConfig.CapDrop)
+	assert.Equal(t, []string{"seccomp=/etc/docker/seccomp.json"}, hostConfig.SecurityOpt)
+	assert.Equal(t, []docker.Device{
+		{PathOnHost: "/dev/fuse", PathInContainer: "/dev/fuse", CgroupPermissions: "rwm"},
+		{PathOnHost: "/dev/sda", PathInContainer: "/dev/xvda", CgroupPermissions: "r"},
+	}, hostConfig.Devices)
+	assert.Equal(t, map[string]string{"/tmp": "rw,size=64m"}, hostConfig.Tmpfs)
+	assert.Equal(t, map[string]string{"net.ipv4.ip_forward": "1"}, hostConfig.Sysctls)
+	assert.Equal(t, "host", hostConfig.UsernsMode)
+	assert.True(t, hostConfig.ReadonlyRootfs)
+}
+
+func TestSecurityOptionsFromAllowedBuildOptions(t *testing.T) {
+	executor := newSecurityExecutor(&common.DockerConfig{
+		CapAdd:         []string{"NET_ADMIN"},
+		AllowedCapAdd:  []string{"SYS_*"},
+		AllowedSysctls: []string{"net.*"},
+		AllowedTmpfs:   []string{"/cache"},
+	}, common.BuildOptions{
+		"cap_add":   []interface{}{"SYS_PTRACE"},
+		"cap_drop":  []interface{}{"MKNOD"},
+		"sysctls":   map[string]interface{}{"net.core.somaxconn": 1024},
+		"tmpfs":     map[string]interface{}{"/cache": "rw,noexec,size=64m"},
+		"read_only": true,
+	})
+
+	options, err := executor.getSecurityOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NET_ADMIN", "SYS_PTRACE"}, options.capAdd)
+	assert.Equal(t, []string{"MKNOD"}, options.capDrop)
+	assert.Equal(t, map[string]string{"net.core.somaxconn": "1024"}, options.sysctls)
+	assert.Equal(t, map[string]string{"/cache": "rw,noexec,size=64m"}, options.tmpfs)
+	assert.True(t, options.readOnly)
+}
+
+func TestSecurityOptionsNotAllowed(t *testing.T) {
+	notAllowedOptions := []common.BuildOptions{
+		{"cap_add": []interface{}{"SYS_ADMIN"}},
+		{"security_opt": []interface{}{"seccomp=unconfined"}},
+		{"devices": []interface{}{"/dev/kmsg"}},
+		{"tmpfs": map[string]interface{}{"/etc": "rw"}},
+		{"tmpfs": map[string]interface{}{"/tmp": "rw,exec"}},
+		{"tmpfs": map[string]interface{}{"/tmp": "suid,size=64m"}},
+		{"sysctls": map[string]interface{}{"kernel.shmmax": "1"}},
+		{"userns_mode": "host"},
+		{"cap_add": "SYS_ADMIN"},
+	}
+
+	for _, options := range notAllowedOptions {
+		executor := newSecurityExecutor(&common.DockerConfig{
+			AllowedCapAdd: []string{"NET_*"},
+			AllowedTmpfs:  []string{"/tmp"},
+		}, options)
+		_, err := executor.getSecurityOptions()
+		assert.Error(t, err, "%v", options)
+	}
+}
+
+func TestSecurityOptionsReadOnlyCannotBeDisabled(t *testing.T) {
+	executor := newSecurityExecutor(&common.DockerConfig{
+		ReadOnly: true,
+	}, common.BuildOptions{
+		"read_only": false,
+	})
+
+	options, err := executor.getSecurityOptions()
+	assert.NoError(t, err)
+	assert.True(t, options.readOnly)
+}
+
+func TestParseInvalidDevice(t *testing.T) {
+	for _, device := range []string{"dev/fuse", "/dev/fuse:fuse", "/a:/b:rwm:x"} {
+		_, err := parseDevice(device)
+		assert.Error(t, err, device)
+	}
+}
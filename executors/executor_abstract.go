@@ -1,15 +1,19 @@
 package executors
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	"bufio"
-	log "github.com/Sirupsen/logrus"
+	"bytes"
+	log "github.com/sirupsen/logrus"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers"
 	"io"
+	"net/url"
 	"strings"
 )
 
@@ -30,6 +34,58 @@ type AbstractExecutor struct {
 	BuildFinish      chan error
 	BuildLog         *io.PipeWriter
 	ShellScript      *common.ShellScript
+	traceWriter      *helpers.MaskingWriter
+	stagesCancel     context.CancelFunc
+	stagesFinished   chan bool
+}
+
+// StageFunc executes the script of the stage,
+// it should stop the execution as soon as ctx is done
+type StageFunc func(ctx context.Context, stage common.ShellScriptStage) error
+
+func (e *AbstractExecutor) getMaskedValues() []string {
+	maskedValues := []string{e.Config.Token}
+
+	addMaskedValue := func(key, value string) {
+		if value == "" {
+			return
+		}
+
+		// masking very short values would make the build log unreadable
+		if len(value) < common.MinMaskedValueLength {
+			e.Warningln("Variable", key, "is shorter than",
+				common.MinMaskedValueLength, "characters and will not be masked.")
+			return
+		}
+		maskedValues = append(maskedValues, value)
+	}
+
+	// the build token is the password of repository URL, it's exported as CI_BUILD_TOKEN
+	if repoURL, err := url.Parse(e.Build.RepoURL); err == nil && repoURL.User != nil {
+		if token, _ := repoURL.User.Password(); token != "" {
+			maskedValues = append(maskedValues, token)
+		}
+	}
+
+	for _, environment := range e.Config.Environment {
+		keyValue := strings.SplitN(environment, "=", 2)
+		if len(keyValue) == 2 {
+			addMaskedValue(keyValue[0], keyValue[1])
+		}
+	}
+
+	for _, variable := range e.Build.Variables {
+		if !variable.Public {
+			addMaskedValue(variable.Key, variable.Value)
+		}
+	}
+	return maskedValues
+}
+
+func (e *AbstractExecutor) flushTrace() {
+	if e.traceWriter != nil {
+		e.traceWriter.Flush()
+	}
 }
 
 func (e *AbstractExecutor) ReadTrace(pipe *io.PipeReader) {
@@ -39,6 +95,7 @@ func (e *AbstractExecutor) ReadTrace(pipe *io.PipeReader) {
 	traceOutputLimit := helpers.NonZeroOrDefault(e.Config.OutputLimit, common.DefaultOutputLimit)
 	traceOutputLimit *= 1024
 
+	var chunk bytes.Buffer
 	reader := bufio.NewReader(pipe)
 	for {
 		r, s, err := reader.ReadRune()
@@ -48,23 +105,32 @@ func (e *AbstractExecutor) ReadTrace(pipe *io.PipeReader) {
 			// ignore symbols if build log exceeded limit
 			continue
 		} else if err == nil {
-			e.Build.WriteRune(r)
+			chunk.WriteRune(r)
 		} else {
 			// ignore invalid characters
 			continue
 		}
 
+		// the masking writer gets all the data read from pipe at once
+		if reader.Buffered() > 0 {
+			continue
+		}
+		e.traceWriter.Write(chunk.Bytes())
+		chunk.Reset()
+
 		if e.Build.BuildLogLen() > traceOutputLimit {
 			output := fmt.Sprintf("\n%sBuild log exceeded limit of %v bytes.%s\n",
 				helpers.ANSI_BOLD_RED,
 				traceOutputLimit,
 				helpers.ANSI_RESET,
 			)
+			e.flushTrace()
 			e.Build.WriteString(output)
 			traceStopped = true
 		}
 	}
 
+	e.flushTrace()
 	pipe.Close()
 }
 
@@ -90,8 +156,7 @@ func (e *AbstractExecutor) PushTrace(config common.RunnerConfig, canceled chan b
 				continue
 			}
 
-			buildTrace := e.Build.BuildLog()
-			switch common.UpdateBuild(config, e.Build.ID, common.Running, buildTrace) {
+			switch e.Build.UpdateTrace(common.Running) {
 			case common.UpdateSucceeded:
 				lastSentTrace = buildTraceLen
 				lastSentTime = time.Now()
@@ -195,9 +260,78 @@ func (e *AbstractExecutor) generateShellScript() error {
 	return nil
 }
 
+func (e *AbstractExecutor) runStage(ctx context.Context, stage common.ShellScriptStage, run StageFunc) error {
+	e.Debugln("Executing", stage, "stage...")
+
+	started := time.Now()
+	err := run(ctx, stage)
+	duration := time.Since(started).Seconds()
+
+	if err != nil {
+		fmt.Fprintf(e.BuildLog, "Stage %s failed after %.1f seconds\n", stage, duration)
+	} else {
+		fmt.Fprintf(e.BuildLog, "Stage %s finished in %.1f seconds\n", stage, duration)
+	}
+	return err
+}
+
+func (e *AbstractExecutor) runStages(ctx context.Context, run StageFunc) (err error) {
+	for _, stage := range common.ShellBuildStages {
+		if _, ok := e.ShellScript.GetStageScript(stage); !ok {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			err = errors.New("build aborted")
+		} else {
+			err = e.runStage(ctx, stage, run)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	// after_script is executed even if the build failed or was aborted
+	if _, ok := e.ShellScript.GetStageScript(common.ShellAfterScript); ok {
+		afterScriptCtx, cancel := context.WithTimeout(context.Background(), common.AfterScriptTimeout)
+		defer cancel()
+		e.runStage(afterScriptCtx, common.ShellAfterScript, run)
+	}
+	return
+}
+
+// StartStages executes the stages of build script in background using run,
+// the result is sent to BuildFinish
+func (e *AbstractExecutor) StartStages(ctx context.Context, run StageFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	e.stagesCancel = cancel
+	e.stagesFinished = make(chan bool)
+
+	go func() {
+		defer close(e.stagesFinished)
+		defer cancel()
+		e.BuildFinish <- e.runStages(ctx, run)
+	}()
+}
+
+// abortStages stops the executed stage and waits for after_script to finish
+func (e *AbstractExecutor) abortStages() {
+	if e.stagesCancel == nil {
+		return
+	}
+	e.stagesCancel()
+
+	select {
+	case <-e.stagesFinished:
+	case <-time.After(common.AfterScriptTimeout):
+		e.Warningln("Timed out waiting for after_script to finish.")
+	}
+}
+
 func (e *AbstractExecutor) startBuild() error {
 	// Create pipe where data are read
 	reader, writer := io.Pipe()
+	e.traceWriter = helpers.NewMaskingWriter(e.Build, e.getMaskedValues())
 	go e.ReadTrace(reader)
 	e.BuildLog = writer
 
@@ -212,13 +346,16 @@ func (e *AbstractExecutor) startBuild() error {
 	return nil
 }
 
+// shellOptions are handled by shells, so they are supported by all executors
+var shellOptions = []string{"before_script", "after_script", "artifacts", "cache"}
+
 func (e *AbstractExecutor) verifyOptions() error {
 	for key, value := range e.Build.Options {
 		if value == nil {
 			continue
 		}
 		found := false
-		for _, option := range e.SupportedOptions {
+		for _, option := range append(shellOptions, e.SupportedOptions...) {
 			if option == key {
 				found = true
 				break
@@ -232,7 +369,7 @@ func (e *AbstractExecutor) verifyOptions() error {
 	return nil
 }
 
-func (e *AbstractExecutor) Prepare(globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
+func (e *AbstractExecutor) Prepare(ctx context.Context, globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
 	e.Config = config
 	e.Build = build
 	e.BuildCanceled = make(chan bool, 1)
@@ -260,30 +397,26 @@ func (e *AbstractExecutor) Prepare(globalConfig *common.Config, config *common.R
 	return nil
 }
 
-func (e *AbstractExecutor) Wait() error {
+func (e *AbstractExecutor) Wait(ctx context.Context) error {
 	e.Build.BuildState = common.Running
 
-	buildTimeout := e.Build.Timeout
-	if buildTimeout <= 0 {
-		buildTimeout = common.DefaultTimeout
-	}
-
 	// Wait for signals: cancel, timeout, abort or finish
 	log.Debugln(e.Config.ShortDescription(), e.Build.ID, "Waiting for signals...")
 	select {
 	case <-e.BuildCanceled:
 		e.Println()
 		e.Warningln("Build got canceled.")
+		e.abortStages()
 		e.Build.FinishBuild(common.Failed)
 
-	case <-time.After(time.Duration(buildTimeout) * time.Second):
-		e.Println()
-		e.Errorln("CI Timeout. Execution took longer then", buildTimeout, "seconds.")
-		e.Build.FinishBuild(common.Failed)
-
-	case signal := <-e.Build.BuildAbort:
+	case <-ctx.Done():
 		e.Println()
-		e.Errorln("Build got aborted:", signal)
+		if ctx.Err() == context.DeadlineExceeded {
+			e.Errorln("CI Timeout. Execution took longer then", e.Build.GetBuildTimeout().Seconds(), "seconds.")
+		} else {
+			e.Errorln("Build got aborted:", ctx.Err())
+		}
+		e.abortStages()
 		e.Build.FinishBuild(common.Failed)
 
 	case err := <-e.BuildFinish:
@@ -298,8 +431,15 @@ func (e *AbstractExecutor) Wait() error {
 	return nil
 }
 
-func (e *AbstractExecutor) Finish(err error) {
-	if err != nil {
+func (e *AbstractExecutor) Finish(ctx context.Context, err error) {
+	// write the rest of trace that could be held back by masking
+	e.flushTrace()
+
+	if _, ok := err.(*common.SystemError); ok {
+		e.Println()
+		e.Errorln("Build failed with system failure:", err)
+		e.Build.FinishBuild(common.Failed)
+	} else if err != nil {
 		e.Println()
 		e.Errorln("Build failed with:", err)
 		e.Build.FinishBuild(common.Failed)
@@ -321,7 +461,7 @@ func (e *AbstractExecutor) Finish(err error) {
 	e.Println("Build finished.")
 }
 
-func (e *AbstractExecutor) Cleanup() {
+func (e *AbstractExecutor) Cleanup(ctx context.Context) {
 	if e.BuildLog != nil {
 		e.BuildLog.Close()
 	}
@@ -0,0 +1,101 @@
+package kubernetes
+
+// Subset of Kubernetes v1 API objects used by executor
+
+type objectMeta struct {
+	Name         string            `json:"name,omitempty"`
+	GenerateName string            `json:"generateName,omitempty"`
+	Namespace    string            `json:"namespace,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+type envVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type resourceRequirements struct {
+	Limits   map[string]string `json:"limits,omitempty"`
+	Requests map[string]string `json:"requests,omitempty"`
+}
+
+type securityContext struct {
+	Privileged *bool `json:"privileged,omitempty"`
+}
+
+type container struct {
+	Name            string               `json:"name"`
+	Image           string               `json:"image"`
+	Command         []string             `json:"command,omitempty"`
+	Env             []envVar             `json:"env,omitempty"`
+	Resources       resourceRequirements `json:"resources"`
+	SecurityContext *securityContext     `json:"securityContext,omitempty"`
+	Stdin           bool                 `json:"stdin,omitempty"`
+	StdinOnce       bool                 `json:"stdinOnce,omitempty"`
+}
+
+type podSpec struct {
+	Containers         []container       `json:"containers"`
+	RestartPolicy      string            `json:"restartPolicy,omitempty"`
+	NodeSelector       map[string]string `json:"nodeSelector,omitempty"`
+	ServiceAccountName string            `json:"serviceAccountName,omitempty"`
+}
+
+type containerStateWaiting struct {
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type containerStateTerminated struct {
+	ExitCode int    `json:"exitCode"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+type containerState struct {
+	Waiting    *containerStateWaiting    `json:"waiting,omitempty"`
+	Terminated *containerStateTerminated `json:"terminated,omitempty"`
+}
+
+type containerStatus struct {
+	Name  string         `json:"name"`
+	State containerState `json:"state"`
+	Ready bool           `json:"ready"`
+}
+
+type podStatus struct {
+	Phase             string            `json:"phase,omitempty"`
+	Reason            string            `json:"reason,omitempty"`
+	Message           string            `json:"message,omitempty"`
+	ContainerStatuses []containerStatus `json:"containerStatuses,omitempty"`
+}
+
+type pod struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Metadata   objectMeta `json:"metadata"`
+	Spec       podSpec    `json:"spec"`
+	Status     podStatus  `json:"status,omitempty"`
+}
+
+type status struct {
+	Message string `json:"message,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Code    int    `json:"code,omitempty"`
+}
+
+const (
+	podPending   = "Pending"
+	podRunning   = "Running"
+	podSucceeded = "Succeeded"
+	podFailed    = "Failed"
+)
+
+func (p *pod) containerStatus(name string) *containerStatus {
+	for idx, status := range p.Status.ContainerStatuses {
+		if status.Name == name {
+			return &p.Status.ContainerStatuses[idx]
+		}
+	}
+	return nil
+}
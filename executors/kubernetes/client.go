@@ -0,0 +1,256 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+type kubeClient struct {
+	host        *url.URL
+	bearerToken string
+	transport   *http.Transport
+	httpClient  *http.Client
+}
+
+func (c *kubeClient) getURL(path string) string {
+	return strings.TrimRight(c.host.String(), "/") + path
+}
+
+func (c *kubeClient) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.getURL(path), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	return req, nil
+}
+
+func (c *kubeClient) do(ctx context.Context, method, path string, request interface{}, response interface{}) error {
+	var body []byte
+	if request != nil {
+		var err error
+		body, err = json.Marshal(request)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := c.newRequest(ctx, method, path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		var status status
+		json.NewDecoder(res.Body).Decode(&status)
+		if status.Message != "" {
+			return fmt.Errorf("%s %s failed: %s", method, path, status.Message)
+		}
+		return fmt.Errorf("%s %s failed: %s", method, path, res.Status)
+	}
+
+	if response != nil {
+		return json.NewDecoder(res.Body).Decode(response)
+	}
+	return nil
+}
+
+func podsPath(namespace string) string {
+	return "/api/v1/namespaces/" + url.QueryEscape(namespace) + "/pods"
+}
+
+func (c *kubeClient) createPod(ctx context.Context, namespace string, newPod *pod) (*pod, error) {
+	var createdPod pod
+	err := c.do(ctx, "POST", podsPath(namespace), newPod, &createdPod)
+	if err != nil {
+		return nil, err
+	}
+	return &createdPod, nil
+}
+
+func (c *kubeClient) getPod(ctx context.Context, namespace, name string) (*pod, error) {
+	var existingPod pod
+	err := c.do(ctx, "GET", podsPath(namespace)+"/"+url.QueryEscape(name), nil, &existingPod)
+	if err != nil {
+		return nil, err
+	}
+	return &existingPod, nil
+}
+
+func (c *kubeClient) deletePod(ctx context.Context, namespace, name string) error {
+	return c.do(ctx, "DELETE", podsPath(namespace)+"/"+url.QueryEscape(name), nil, nil)
+}
+
+// attach streams stdin to container and copies its output
+// until the container closes the connection or ctx is done
+func (c *kubeClient) attach(ctx context.Context, namespace, name, containerName string, stdin io.Reader, stdout, stderr io.Writer) error {
+	query := url.Values{}
+	query.Set("container", containerName)
+	query.Set("stdin", "true")
+	query.Set("stdout", "true")
+	query.Set("stderr", "true")
+
+	conn, err := c.dialWebsocket(ctx, podsPath(namespace)+"/"+url.QueryEscape(name)+"/attach?"+query.Encode())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	attachFinished := make(chan bool)
+	defer close(attachFinished)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-attachFinished:
+		}
+	}()
+
+	go func() {
+		data := make([]byte, 32*1024)
+		for {
+			n, err := stdin.Read(data)
+			if n > 0 {
+				payload := append([]byte{stdinChannel}, data[0:n]...)
+				if conn.WriteMessage(websocket.BinaryMessage, payload) != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var errorMessage bytes.Buffer
+	for {
+		messageType, payload, err := conn.ReadMessage()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		} else if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseAbnormalClosure) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if messageType != websocket.BinaryMessage || len(payload) == 0 {
+			continue
+		}
+
+		switch payload[0] {
+		case stdoutChannel:
+			stdout.Write(payload[1:])
+		case stderrChannel:
+			stderr.Write(payload[1:])
+		case errorChannel:
+			errorMessage.Write(payload[1:])
+		}
+	}
+
+	if errorMessage.Len() > 0 {
+		return errors.New(strings.TrimSpace(errorMessage.String()))
+	}
+	return nil
+}
+
+func newTLSConfig(config *common.KubernetesConfig, caFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	if config.CertFile != "" && config.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if caFile != "" {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+	}
+	return tlsConfig, nil
+}
+
+func newKubeClient(config *common.KubernetesConfig) (*kubeClient, error) {
+	host := config.Host
+	bearerToken := config.BearerToken
+	caFile := config.CAFile
+
+	// use service account when running inside of cluster
+	if host == "" {
+		serviceHost, servicePort := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if serviceHost == "" || servicePort == "" {
+			return nil, errors.New("Missing Kubernetes host and not running inside of cluster")
+		}
+		host = "https://" + net.JoinHostPort(serviceHost, servicePort)
+
+		if bearerToken == "" {
+			token, err := ioutil.ReadFile(filepath.Join(serviceAccountDir, "token"))
+			if err != nil {
+				return nil, err
+			}
+			bearerToken = strings.TrimSpace(string(token))
+		}
+		if caFile == "" {
+			caFile = filepath.Join(serviceAccountDir, "ca.crt")
+		}
+	}
+
+	hostURL, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(config, caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+
+	return &kubeClient{
+		host:        hostURL,
+		bearerToken: bearerToken,
+		transport:   transport,
+		httpClient:  &http.Client{Transport: transport},
+	}, nil
+}
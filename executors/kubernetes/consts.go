@@ -0,0 +1,10 @@
+package kubernetes
+
+import "time"
+
+const defaultPollInterval = 3
+const defaultPollTimeout = 180
+const podStatusPollInterval = time.Second
+const buildContainerExitTimeout = time.Minute
+const buildContainerName = "build"
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
@@ -0,0 +1,388 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors"
+)
+
+var invalidNameCharacters = regexp.MustCompile("[^a-z0-9-]+")
+
+type KubernetesExecutor struct {
+	executors.AbstractExecutor
+	client    *kubeClient
+	namespace string
+	pod       *pod
+}
+
+func (s *KubernetesExecutor) verifyAllowedImage(image, optionName string, allowedImages []string, internalImages []string) error {
+	for _, allowedImage := range allowedImages {
+		ok, _ := filepath.Match(allowedImage, image)
+		if ok {
+			return nil
+		}
+	}
+
+	for _, internalImage := range internalImages {
+		if internalImage == image {
+			return nil
+		}
+	}
+
+	// by default allow to override the image name
+	if len(allowedImages) == 0 {
+		return nil
+	}
+
+	s.Println()
+	s.Errorln("The", image, "is not present on list of allowed", optionName)
+	for _, allowedImage := range allowedImages {
+		s.Println("-", allowedImage)
+	}
+	s.Println()
+	return errors.New("invalid image")
+}
+
+func (s *KubernetesExecutor) getImageName() (string, error) {
+	if imageOption, ok := s.Build.Options["image"].(string); ok && imageOption != "" {
+		err := s.verifyAllowedImage(imageOption, "images", s.Config.Kubernetes.AllowedImages, []string{s.Config.Kubernetes.Image})
+		if err != nil {
+			return "", err
+		}
+		return imageOption, nil
+	}
+
+	if s.Config.Kubernetes.Image == "" {
+		return "", errors.New("No image specified")
+	}
+	return s.Config.Kubernetes.Image, nil
+}
+
+func (s *KubernetesExecutor) getServiceNames() ([]string, error) {
+	services := s.Config.Kubernetes.Services
+
+	if servicesOption, ok := s.Build.Options["services"].([]interface{}); ok {
+		for _, service := range servicesOption {
+			serviceName, ok := service.(string)
+			if !ok {
+				s.Errorln("Invalid service name passed:", service)
+				return nil, errors.New("invalid service name")
+			}
+
+			err := s.verifyAllowedImage(serviceName, "services", s.Config.Kubernetes.AllowedServices, s.Config.Kubernetes.Services)
+			if err != nil {
+				return nil, err
+			}
+
+			services = append(services, serviceName)
+		}
+	}
+
+	return services, nil
+}
+
+func (s *KubernetesExecutor) getServiceVariables() []envVar {
+	variables := getEnvVars(s.Config.Environment)
+
+	for _, buildVariable := range s.Build.Variables {
+		if buildVariable.Public {
+			variables = append(variables, envVar{Name: buildVariable.Key, Value: buildVariable.Value})
+		}
+	}
+	return variables
+}
+
+func getEnvVars(environment []string) []envVar {
+	var variables []envVar
+	for _, keyValue := range environment {
+		parts := strings.SplitN(keyValue, "=", 2)
+		if len(parts) == 2 {
+			variables = append(variables, envVar{Name: parts[0], Value: parts[1]})
+		}
+	}
+	return variables
+}
+
+func getResources(cpuRequest, memoryRequest, cpuLimit, memoryLimit string) resourceRequirements {
+	resources := resourceRequirements{
+		Requests: make(map[string]string),
+		Limits:   make(map[string]string),
+	}
+
+	if cpuRequest != "" {
+		resources.Requests["cpu"] = cpuRequest
+	}
+	if memoryRequest != "" {
+		resources.Requests["memory"] = memoryRequest
+	}
+	if cpuLimit != "" {
+		resources.Limits["cpu"] = cpuLimit
+	}
+	if memoryLimit != "" {
+		resources.Limits["memory"] = memoryLimit
+	}
+	return resources
+}
+
+func (s *KubernetesExecutor) getPodName() string {
+	name := strings.ToLower(s.Build.ProjectUniqueName())
+	name = invalidNameCharacters.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-") + "-"
+}
+
+func (s *KubernetesExecutor) getLabels() map[string]string {
+	return map[string]string{
+		"gitlab-runner/build.id":   strconv.Itoa(s.Build.ID),
+		"gitlab-runner/project.id": strconv.Itoa(s.Build.ProjectID),
+		"gitlab-runner/runner.id":  invalidNameCharacters.ReplaceAllString(strings.ToLower(s.Build.Runner.ShortDescription()), "-"),
+	}
+}
+
+func (s *KubernetesExecutor) buildPod(imageName string, serviceNames []string) *pod {
+	config := s.Config.Kubernetes
+
+	buildContainer := container{
+		Name:      buildContainerName,
+		Image:     imageName,
+		Command:   s.ShellScript.GetCommandWithArguments(),
+		Env:       getEnvVars(s.ShellScript.Environment),
+		Resources: getResources(config.CPURequest, config.MemoryRequest, config.CPULimit, config.MemoryLimit),
+		Stdin:     true,
+		StdinOnce: true,
+	}
+	if config.Privileged {
+		privileged := true
+		buildContainer.SecurityContext = &securityContext{
+			Privileged: &privileged,
+		}
+	}
+
+	containers := []container{buildContainer}
+	for idx, serviceName := range serviceNames {
+		containers = append(containers, container{
+			Name:      fmt.Sprintf("svc-%d", idx),
+			Image:     serviceName,
+			Env:       s.getServiceVariables(),
+			Resources: getResources(config.ServiceCPURequest, config.ServiceMemoryRequest, config.ServiceCPULimit, config.ServiceMemoryLimit),
+		})
+	}
+
+	return &pod{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Metadata: objectMeta{
+			GenerateName: s.getPodName(),
+			Namespace:    s.namespace,
+			Labels:       s.getLabels(),
+		},
+		Spec: podSpec{
+			Containers:         containers,
+			RestartPolicy:      "Never",
+			NodeSelector:       config.NodeSelector,
+			ServiceAccountName: config.ServiceAccount,
+		},
+	}
+}
+
+func (s *KubernetesExecutor) waitForPodRunning(ctx context.Context) error {
+	pollInterval := s.Config.Kubernetes.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	pollTimeout := s.Config.Kubernetes.PollTimeout
+	if pollTimeout <= 0 {
+		pollTimeout = defaultPollTimeout
+	}
+
+	timeout := time.After(time.Duration(pollTimeout) * time.Second)
+	for {
+		pod, err := s.client.getPod(ctx, s.namespace, s.pod.Metadata.Name)
+		if err != nil {
+			return err
+		}
+
+		switch pod.Status.Phase {
+		case podRunning:
+			return nil
+
+		case podSucceeded, podFailed:
+			return fmt.Errorf("pod %s finished before build started: %s", pod.Metadata.Name, pod.Status.Message)
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			if waiting := status.State.Waiting; waiting != nil {
+				switch waiting.Reason {
+				case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+					return fmt.Errorf("container %s failed to start: %s %s", status.Name, waiting.Reason, waiting.Message)
+				}
+			}
+		}
+
+		s.Println("Waiting for pod", s.namespace+"/"+pod.Metadata.Name, "to be running, status is", pod.Status.Phase)
+
+		select {
+		case <-time.After(time.Duration(pollInterval) * time.Second):
+		case <-timeout:
+			return fmt.Errorf("timed out waiting for pod %s to start", pod.Metadata.Name)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *KubernetesExecutor) waitForBuildContainer(ctx context.Context) (int, error) {
+	// the container exits right after the attached script finishes
+	ctx, cancel := context.WithTimeout(ctx, buildContainerExitTimeout)
+	defer cancel()
+
+	for {
+		pod, err := s.client.getPod(ctx, s.namespace, s.pod.Metadata.Name)
+		if err != nil {
+			return -1, err
+		}
+
+		if status := pod.containerStatus(buildContainerName); status != nil && status.State.Terminated != nil {
+			return status.State.Terminated.ExitCode, nil
+		}
+
+		select {
+		case <-time.After(podStatusPollInterval):
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		}
+	}
+}
+
+func (s *KubernetesExecutor) runBuild(ctx context.Context) error {
+	err := s.waitForPodRunning(ctx)
+	if err != nil {
+		return err
+	}
+
+	// shell reads script from stdin, which can't be closed over attach
+	script := strings.NewReader(s.ShellScript.Script + "\nexit\n")
+
+	s.Debugln("Attaching to pod", s.pod.Metadata.Name, "...")
+	err = s.client.attach(ctx, s.namespace, s.pod.Metadata.Name, buildContainerName, script, s.BuildLog, s.BuildLog)
+	if err != nil {
+		return err
+	}
+
+	s.Debugln("Waiting for build container...")
+	exitCode, err := s.waitForBuildContainer(ctx)
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("exit code %d", exitCode)
+	}
+	return nil
+}
+
+func (s *KubernetesExecutor) Prepare(ctx context.Context, globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
+	err := s.AbstractExecutor.Prepare(ctx, globalConfig, config, build)
+	if err != nil {
+		return err
+	}
+
+	if s.ShellScript.PassFile {
+		return errors.New("Kubernetes doesn't support shells that require script file")
+	}
+
+	if config.Kubernetes == nil {
+		return errors.New("Missing kubernetes configuration")
+	}
+
+	imageName, err := s.getImageName()
+	if err != nil {
+		return err
+	}
+	s.Println("Using Kubernetes executor with image", imageName, "...")
+
+	s.namespace = config.Kubernetes.Namespace
+	if s.namespace == "" {
+		s.namespace = "default"
+	}
+
+	client, err := newKubeClient(config.Kubernetes)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	return nil
+}
+
+func (s *KubernetesExecutor) Start(ctx context.Context) error {
+	s.Debugln("Starting Kubernetes pod...")
+
+	imageName, err := s.getImageName()
+	if err != nil {
+		return err
+	}
+
+	serviceNames, err := s.getServiceNames()
+	if err != nil {
+		return err
+	}
+
+	pod, err := s.client.createPod(ctx, s.namespace, s.buildPod(imageName, serviceNames))
+	if err != nil {
+		return err
+	}
+	s.pod = pod
+	s.Debugln("Created pod", pod.Metadata.Name, "...")
+
+	go func() {
+		s.BuildFinish <- s.runBuild(ctx)
+	}()
+	return nil
+}
+
+func (s *KubernetesExecutor) Cleanup(ctx context.Context) {
+	if s.pod != nil {
+		err := s.client.deletePod(ctx, s.namespace, s.pod.Metadata.Name)
+		s.Debugln("Removed pod", s.pod.Metadata.Name, "with", err)
+		s.pod = nil
+	}
+
+	s.AbstractExecutor.Cleanup(ctx)
+}
+
+func init() {
+	options := executors.ExecutorOptions{
+		DefaultBuildsDir: "/builds",
+		SharedBuildsDir:  false,
+		Shell: common.ShellScriptInfo{
+			Shell: "bash",
+			Type:  common.NormalShell,
+		},
+		ShowHostname:     true,
+		SupportedOptions: []string{"image", "services"},
+	}
+
+	create := func() common.Executor {
+		return &KubernetesExecutor{
+			AbstractExecutor: executors.AbstractExecutor{
+				ExecutorOptions: options,
+			},
+		}
+	}
+
+	common.RegisterExecutor("kubernetes", common.ExecutorFactory{
+		Create: create,
+		Features: common.FeaturesInfo{
+			Variables: true,
+			Image:     true,
+			Services:  true,
+		},
+	})
+}
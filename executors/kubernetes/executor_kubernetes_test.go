@@ -0,0 +1,232 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+type fakeAPIServer struct {
+	t           *testing.T
+	pods        map[string]*pod
+	stdin       bytes.Buffer
+	exitCode    int
+	deletedPods []string
+	lock        sync.Mutex
+}
+
+func (f *fakeAPIServer) handleAttach(w http.ResponseWriter, r *http.Request) {
+	assert.Equal(f.t, "websocket", r.Header.Get("Upgrade"))
+	assert.Equal(f.t, buildContainerName, r.URL.Query().Get("container"))
+
+	upgrader := websocket.Upgrader{Subprotocols: []string{websocketProtocol}}
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	defer ws.Close()
+	assert.Equal(f.t, websocketProtocol, ws.Subprotocol())
+
+	for !strings.HasSuffix(f.stdin.String(), "\nexit\n") {
+		messageType, payload, err := ws.ReadMessage()
+		if err != nil {
+			f.t.Fatal(err)
+		}
+		assert.Equal(f.t, websocket.BinaryMessage, messageType)
+		assert.Equal(f.t, byte(stdinChannel), payload[0])
+		f.stdin.Write(payload[1:])
+	}
+
+	ws.WriteMessage(websocket.BinaryMessage, append([]byte{stdoutChannel}, []byte("build output\n")...))
+	ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+func (f *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	prefix := "/api/v1/namespaces/test/pods"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case r.Method == "POST" && name == "":
+		var newPod pod
+		assert.NoError(f.t, json.NewDecoder(r.Body).Decode(&newPod))
+		newPod.Metadata.Name = newPod.Metadata.GenerateName + "abcde"
+		newPod.Status.Phase = podPending
+		f.pods[newPod.Metadata.Name] = &newPod
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(newPod)
+
+	case r.Method == "GET" && strings.HasSuffix(name, "/attach"):
+		existingPod := f.pods[strings.TrimSuffix(name, "/attach")]
+		existingPod.Status.ContainerStatuses = []containerStatus{
+			{
+				Name: buildContainerName,
+				State: containerState{
+					Terminated: &containerStateTerminated{ExitCode: f.exitCode},
+				},
+			},
+		}
+		f.handleAttach(w, r)
+
+	case r.Method == "GET" && f.pods[name] != nil:
+		json.NewEncoder(w).Encode(f.pods[name])
+		f.pods[name].Status.Phase = podRunning
+
+	case r.Method == "DELETE" && f.pods[name] != nil:
+		f.deletedPods = append(f.deletedPods, name)
+		delete(f.pods, name)
+		json.NewEncoder(w).Encode(status{})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func runFakeBuild(t *testing.T, exitCode int) (*fakeAPIServer, *common.Build, error) {
+	apiServer := &fakeAPIServer{
+		t:        t,
+		pods:     make(map[string]*pod),
+		exitCode: exitCode,
+	}
+	server := httptest.NewServer(apiServer)
+	defer server.Close()
+
+	coordinator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer coordinator.Close()
+
+	build := &common.Build{
+		GetBuildResponse: common.GetBuildResponse{
+			ID:        1,
+			ProjectID: 2,
+			Commands:  "make test",
+			RepoURL:   "https://gitlab.com/gitlab-org/gitlab-ce.git",
+			Sha:       "1234567890abcdef",
+			RefName:   "master",
+			Options: common.BuildOptions{
+				"image":    "ruby:2.1",
+				"services": []interface{}{"mysql:5.6"},
+			},
+		},
+		Runner: &common.RunnerConfig{
+			RunnerCredentials: common.RunnerCredentials{
+				URL:   coordinator.URL,
+				Token: "runner-token",
+			},
+			Executor: "kubernetes",
+			Kubernetes: &common.KubernetesConfig{
+				Host:         server.URL,
+				Image:        "ruby:2.0",
+				Namespace:    "test",
+				CPULimit:     "1",
+				NodeSelector: map[string]string{"disk": "ssd"},
+				PollInterval: 1,
+			},
+		},
+	}
+
+	err := build.Run(context.Background(), common.NewConfig())
+	return apiServer, build, err
+}
+
+func TestKubernetesBuild(t *testing.T) {
+	apiServer, build, err := runFakeBuild(t, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, common.BuildState(common.Success), build.BuildState)
+	assert.Contains(t, build.BuildLog(), "build output")
+	assert.Contains(t, apiServer.stdin.String(), "make test")
+	assert.Equal(t, []string{"runner-runner-t-project-2-concurrent-0-abcde"}, apiServer.deletedPods)
+}
+
+func TestKubernetesBuildFailure(t *testing.T) {
+	_, _, err := runFakeBuild(t, 1)
+	assert.EqualError(t, err, "exit code 1")
+}
+
+func TestKubernetesBuildPod(t *testing.T) {
+	executor := &KubernetesExecutor{}
+	executor.Config = &common.RunnerConfig{
+		Kubernetes: &common.KubernetesConfig{
+			CPURequest:     "500m",
+			MemoryLimit:    "1Gi",
+			ServiceAccount: "builder",
+			NodeSelector:   map[string]string{"disk": "ssd"},
+			Privileged:     true,
+		},
+	}
+	executor.Build = &common.Build{
+		Runner: executor.Config,
+		GetBuildResponse: common.GetBuildResponse{
+			Variables: []common.BuildVariable{
+				{Key: "PUBLIC", Value: "value", Public: true},
+				{Key: "SECRET", Value: "secret"},
+			},
+		},
+	}
+	executor.ShellScript = &common.ShellScript{
+		Command:     "bash",
+		Environment: []string{"KEY=VALUE"},
+	}
+	executor.namespace = "test"
+
+	pod := executor.buildPod("ruby:2.1", []string{"mysql:5.6"})
+	assert.Equal(t, "test", pod.Metadata.Namespace)
+	assert.Equal(t, "builder", pod.Spec.ServiceAccountName)
+	assert.Equal(t, map[string]string{"disk": "ssd"}, pod.Spec.NodeSelector)
+	assert.Len(t, pod.Spec.Containers, 2)
+
+	build := pod.Spec.Containers[0]
+	assert.Equal(t, "ruby:2.1", build.Image)
+	assert.Equal(t, []string{"bash"}, build.Command)
+	assert.Equal(t, []envVar{{Name: "KEY", Value: "VALUE"}}, build.Env)
+	assert.Equal(t, "500m", build.Resources.Requests["cpu"])
+	assert.Equal(t, "1Gi", build.Resources.Limits["memory"])
+	assert.True(t, *build.SecurityContext.Privileged)
+
+	service := pod.Spec.Containers[1]
+	assert.Equal(t, "mysql:5.6", service.Image)
+	assert.Equal(t, []envVar{{Name: "PUBLIC", Value: "value"}}, service.Env)
+}
+
+func TestWebsocketUsesProxyOfTransport(t *testing.T) {
+	proxyRequests := make(chan *http.Request, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyRequests <- r
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	client, err := newKubeClient(&common.KubernetesConfig{Host: "http://kubernetes.example.com:8080"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	client.transport.Proxy = http.ProxyURL(proxyURL)
+
+	_, err = client.dialWebsocket(context.Background(), "/api/v1/namespaces/test/pods/build/attach")
+	assert.Error(t, err)
+
+	request := <-proxyRequests
+	assert.Equal(t, "CONNECT", request.Method)
+	assert.Equal(t, "kubernetes.example.com:8080", request.Host)
+}
@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+)
+
+// Kubernetes streaming endpoints are accessed with WebSocket using channel.k8s.io protocol,
+// every message is prefixed with the number of stream it belongs to
+const websocketProtocol = "channel.k8s.io"
+
+// maxWebsocketMessageSize limits the memory allocated for a message received from server
+const maxWebsocketMessageSize = 16 * 1024 * 1024
+
+const (
+	stdinChannel  = 0
+	stdoutChannel = 1
+	stderrChannel = 2
+	errorChannel  = 3
+)
+
+// dialWebsocket connects to streaming endpoint of API server,
+// the connection uses the same proxy and TLS configuration as the other requests
+func (c *kubeClient) dialWebsocket(ctx context.Context, path string) (*websocket.Conn, error) {
+	websocketURL, err := url.Parse(c.getURL(path))
+	if err != nil {
+		return nil, err
+	}
+
+	if websocketURL.Scheme == "https" {
+		websocketURL.Scheme = "wss"
+	} else {
+		websocketURL.Scheme = "ws"
+	}
+
+	header := http.Header{}
+	if c.bearerToken != "" {
+		header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	dialer := websocket.Dialer{
+		Proxy:           c.transport.Proxy,
+		TLSClientConfig: c.transport.TLSClientConfig,
+		Subprotocols:    []string{websocketProtocol},
+	}
+
+	conn, res, err := dialer.DialContext(ctx, websocketURL.String(), header)
+	if err == websocket.ErrBadHandshake && res != nil {
+		return nil, fmt.Errorf("failed to upgrade connection: %s", res.Status)
+	} else if err != nil {
+		return nil, err
+	}
+
+	conn.SetReadLimit(maxWebsocketMessageSize)
+	return conn, nil
+}
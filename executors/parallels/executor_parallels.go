@@ -1,6 +1,7 @@
 package parallels
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os/exec"
@@ -25,7 +26,7 @@ type ParallelsExecutor struct {
 	machineVerified bool
 }
 
-func (s *ParallelsExecutor) waitForIPAddress(vmName string, seconds int) (string, error) {
+func (s *ParallelsExecutor) waitForIPAddress(ctx context.Context, vmName string, seconds int) (string, error) {
 	var lastError error
 
 	if s.ipAddress != "" {
@@ -47,17 +48,22 @@ func (s *ParallelsExecutor) waitForIPAddress(vmName string, seconds int) (string
 			return ipAddr, nil
 		}
 		lastError = err
-		time.Sleep(time.Second)
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
 	}
 	return "", lastError
 }
 
-func (s *ParallelsExecutor) verifyMachine(vmName string) error {
+func (s *ParallelsExecutor) verifyMachine(ctx context.Context, vmName string) error {
 	if s.machineVerified {
 		return nil
 	}
 
-	ipAddr, err := s.waitForIPAddress(vmName, 120)
+	ipAddr, err := s.waitForIPAddress(ctx, vmName, 120)
 	if err != nil {
 		return err
 	}
@@ -73,12 +79,12 @@ func (s *ParallelsExecutor) verifyMachine(vmName string) error {
 	sshCommand.Host = &ipAddr
 
 	s.Debugln("Connecting to SSH...")
-	err = sshCommand.Connect()
+	err = sshCommand.Connect(ctx)
 	if err != nil {
 		return err
 	}
 	defer sshCommand.Cleanup()
-	err = sshCommand.Run()
+	err = sshCommand.Run(ctx)
 	if err != nil {
 		return err
 	}
@@ -102,7 +108,7 @@ func (s *ParallelsExecutor) restoreFromSnapshot() error {
 	return nil
 }
 
-func (s *ParallelsExecutor) createVM() error {
+func (s *ParallelsExecutor) createVM(ctx context.Context) error {
 	baseImage := s.Config.Parallels.BaseName
 	if baseImage == "" {
 		return errors.New("Missing Image setting from Parallels config")
@@ -143,15 +149,15 @@ func (s *ParallelsExecutor) createVM() error {
 	}
 
 	s.Debugln("Waiting for VM to become responsive...")
-	err = s.verifyMachine(s.vmName)
+	err = s.verifyMachine(ctx, s.vmName)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *ParallelsExecutor) Prepare(globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
-	err := s.AbstractExecutor.Prepare(globalConfig, config, build)
+func (s *ParallelsExecutor) Prepare(ctx context.Context, globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
+	err := s.AbstractExecutor.Prepare(ctx, globalConfig, config, build)
 	if err != nil {
 		return err
 	}
@@ -213,7 +219,7 @@ func (s *ParallelsExecutor) Prepare(globalConfig *common.Config, config *common.
 
 	if !prl.Exist(s.vmName) {
 		s.Println("Creating new VM...")
-		err := s.createVM()
+		err := s.createVM(ctx)
 		if err != nil {
 			return err
 		}
@@ -251,7 +257,7 @@ func (s *ParallelsExecutor) Prepare(globalConfig *common.Config, config *common.
 	}
 
 	s.Println("Waiting VM to become responsive...")
-	err = s.verifyMachine(s.vmName)
+	err = s.verifyMachine(ctx, s.vmName)
 	if err != nil {
 		return err
 	}
@@ -266,8 +272,8 @@ func (s *ParallelsExecutor) Prepare(globalConfig *common.Config, config *common.
 	return nil
 }
 
-func (s *ParallelsExecutor) Start() error {
-	ipAddr, err := s.waitForIPAddress(s.vmName, 60)
+func (s *ParallelsExecutor) Start(ctx context.Context) error {
+	ipAddr, err := s.waitForIPAddress(ctx, s.vmName, 60)
 	if err != nil {
 		return err
 	}
@@ -277,29 +283,29 @@ func (s *ParallelsExecutor) Start() error {
 		Config:      *s.Config.SSH,
 		Environment: s.ShellScript.Environment,
 		Command:     s.ShellScript.GetFullCommand(),
-		Stdin:       s.ShellScript.GetScriptBytes(),
 		Stdout:      s.BuildLog,
 		Stderr:      s.BuildLog,
 	}
 	s.sshCommand.Host = &ipAddr
 
 	s.Debugln("Connecting to SSH server...")
-	err = s.sshCommand.Connect()
+	err = s.sshCommand.Connect(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Wait for process to exit
-	go func() {
-		s.Debugln("Will run SSH command...")
-		err := s.sshCommand.Run()
-		s.Debugln("SSH command finished with", err)
-		s.BuildFinish <- err
-	}()
+	s.StartStages(ctx, s.runStage)
 	return nil
 }
 
-func (s *ParallelsExecutor) Cleanup() {
+func (s *ParallelsExecutor) runStage(ctx context.Context, stage common.ShellScriptStage) error {
+	s.sshCommand.Stdin = s.ShellScript.GetStageScriptBytes(stage)
+	err := s.sshCommand.Run(ctx)
+	s.Debugln("SSH command finished with", err)
+	return err
+}
+
+func (s *ParallelsExecutor) Cleanup(ctx context.Context) {
 	s.sshCommand.Cleanup()
 
 	if s.vmName != "" {
@@ -310,7 +316,7 @@ func (s *ParallelsExecutor) Cleanup() {
 		}
 	}
 
-	s.AbstractExecutor.Cleanup()
+	s.AbstractExecutor.Cleanup(ctx)
 }
 
 func init() {
@@ -2,11 +2,15 @@ package shell
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
+
+	"github.com/kardianos/osext"
 
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors"
@@ -16,15 +20,19 @@ import (
 type ShellExecutor struct {
 	executors.AbstractExecutor
 	cmd       *exec.Cmd
+	cmdLock   sync.Mutex
 	scriptDir string
 }
 
-func (s *ShellExecutor) Prepare(globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
+func (s *ShellExecutor) Prepare(ctx context.Context, globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
 	if globalConfig != nil {
 		s.Shell.User = globalConfig.User
 	}
 
-	err := s.AbstractExecutor.Prepare(globalConfig, config, build)
+	// the helper commands are executed by the same runner binary
+	s.Shell.RunnerCommand, _ = osext.Executable()
+
+	err := s.AbstractExecutor.Prepare(ctx, globalConfig, config, build)
 	if err != nil {
 		return err
 	}
@@ -33,61 +41,85 @@ func (s *ShellExecutor) Prepare(globalConfig *common.Config, config *common.Runn
 	return nil
 }
 
-func (s *ShellExecutor) Start() error {
-	s.Debugln("Starting shell command...")
-
+func (s *ShellExecutor) runStage(ctx context.Context, stage common.ShellScriptStage) error {
 	// Create execution command
-	s.cmd = exec.Command(s.ShellScript.Command, s.ShellScript.Arguments...)
-	if s.cmd == nil {
+	cmd := exec.Command(s.ShellScript.Command, s.ShellScript.Arguments...)
+	if cmd == nil {
 		return errors.New("Failed to generate execution command")
 	}
 
-	helpers.SetProcessGroup(s.cmd)
+	helpers.SetProcessGroup(cmd)
 
 	// Fill process environment variables
-	s.cmd.Env = append(os.Environ(), s.ShellScript.Environment...)
-	s.cmd.Stdout = s.BuildLog
-	s.cmd.Stderr = s.BuildLog
+	cmd.Env = append(os.Environ(), s.ShellScript.Environment...)
+	cmd.Stdout = s.BuildLog
+	cmd.Stderr = s.BuildLog
 
 	if s.ShellScript.PassFile {
-		scriptDir, err := ioutil.TempDir("", "build_script")
-		if err != nil {
-			return err
-		}
-		s.scriptDir = scriptDir
-
-		scriptFile := filepath.Join(scriptDir, "script."+s.ShellScript.Extension)
-		err = ioutil.WriteFile(scriptFile, s.ShellScript.GetScriptBytes(), 0700)
+		scriptFile := filepath.Join(s.scriptDir, string(stage)+"."+s.ShellScript.Extension)
+		err := ioutil.WriteFile(scriptFile, s.ShellScript.GetStageScriptBytes(stage), 0700)
 		if err != nil {
 			return err
 		}
 
-		s.cmd.Args = append(s.cmd.Args, scriptFile)
+		cmd.Args = append(cmd.Args, scriptFile)
 	} else {
-		s.cmd.Stdin = bytes.NewReader(s.ShellScript.GetScriptBytes())
+		cmd.Stdin = bytes.NewReader(s.ShellScript.GetStageScriptBytes(stage))
 	}
 
 	// Start process
-	err := s.cmd.Start()
+	s.cmdLock.Lock()
+	err := cmd.Start()
+	if err == nil {
+		s.cmd = cmd
+	}
+	s.cmdLock.Unlock()
 	if err != nil {
 		return errors.New("Failed to start process")
 	}
 
 	// Wait for process to exit
+	waitCh := make(chan error, 1)
 	go func() {
-		s.BuildFinish <- s.cmd.Wait()
+		waitCh <- cmd.Wait()
 	}()
+
+	select {
+	case err = <-waitCh:
+		return err
+
+	case <-ctx.Done():
+		helpers.KillProcessGroup(cmd)
+		<-waitCh
+		return errors.New("aborted")
+	}
+}
+
+func (s *ShellExecutor) Start(ctx context.Context) error {
+	s.Debugln("Starting shell command...")
+
+	if s.ShellScript.PassFile {
+		scriptDir, err := ioutil.TempDir("", "build_script")
+		if err != nil {
+			return err
+		}
+		s.scriptDir = scriptDir
+	}
+
+	s.StartStages(ctx, s.runStage)
 	return nil
 }
 
-func (s *ShellExecutor) Cleanup() {
+func (s *ShellExecutor) Cleanup(ctx context.Context) {
+	s.cmdLock.Lock()
 	helpers.KillProcessGroup(s.cmd)
+	s.cmdLock.Unlock()
 
 	if s.scriptDir != "" {
 		os.RemoveAll(s.scriptDir)
 	}
 
-	s.AbstractExecutor.Cleanup()
+	s.AbstractExecutor.Cleanup(ctx)
 }
 
 func init() {
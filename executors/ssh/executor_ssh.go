@@ -1,6 +1,7 @@
 package ssh
 
 import (
+	"context"
 	"errors"
 
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
@@ -13,8 +14,8 @@ type SSHExecutor struct {
 	sshCommand ssh.Command
 }
 
-func (s *SSHExecutor) Prepare(globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
-	err := s.AbstractExecutor.Prepare(globalConfig, config, build)
+func (s *SSHExecutor) Prepare(ctx context.Context, globalConfig *common.Config, config *common.RunnerConfig, build *common.Build) error {
+	err := s.AbstractExecutor.Prepare(ctx, globalConfig, config, build)
 	if err != nil {
 		return err
 	}
@@ -26,7 +27,7 @@ func (s *SSHExecutor) Prepare(globalConfig *common.Config, config *common.Runner
 	return nil
 }
 
-func (s *SSHExecutor) Start() error {
+func (s *SSHExecutor) Start(ctx context.Context) error {
 	if s.Config.SSH == nil {
 		return errors.New("Missing SSH configuration")
 	}
@@ -38,30 +39,30 @@ func (s *SSHExecutor) Start() error {
 		Config:      *s.Config.SSH,
 		Environment: s.ShellScript.Environment,
 		Command:     s.ShellScript.GetFullCommand(),
-		Stdin:       s.ShellScript.GetScriptBytes(),
 		Stdout:      s.BuildLog,
 		Stderr:      s.BuildLog,
 	}
 
 	s.Debugln("Connecting to SSH server...")
-	err := s.sshCommand.Connect()
+	err := s.sshCommand.Connect(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Wait for process to exit
-	go func() {
-		s.Debugln("Will run SSH command...")
-		err := s.sshCommand.Run()
-		s.Debugln("SSH command finished with", err)
-		s.BuildFinish <- err
-	}()
+	s.StartStages(ctx, s.runStage)
 	return nil
 }
 
-func (s *SSHExecutor) Cleanup() {
+func (s *SSHExecutor) runStage(ctx context.Context, stage common.ShellScriptStage) error {
+	s.sshCommand.Stdin = s.ShellScript.GetStageScriptBytes(stage)
+	err := s.sshCommand.Run(ctx)
+	s.Debugln("SSH command finished with", err)
+	return err
+}
+
+func (s *SSHExecutor) Cleanup(ctx context.Context) {
 	s.sshCommand.Cleanup()
-	s.AbstractExecutor.Cleanup()
+	s.AbstractExecutor.Cleanup(ctx)
 }
 
 func init() {
@@ -0,0 +1,76 @@
+package archives
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func createZipFileEntry(archive *zip.Writer, fileName string, fi os.FileInfo) error {
+	header, err := zip.FileInfoHeader(fi)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(fileName)
+
+	switch {
+	case fi.Mode().IsDir():
+		header.Name += "/"
+		_, err = archive.CreateHeader(header)
+		return err
+
+	case fi.Mode()&os.ModeSymlink != 0:
+		// symlinks are stored with the link target as content
+		link, err := os.Readlink(fileName)
+		if err != nil {
+			return err
+		}
+
+		w, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, link)
+		return err
+
+	case fi.Mode().IsRegular():
+		header.Method = zip.Deflate
+
+		file, err := os.Open(fileName)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		w, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, file)
+		return err
+
+	default:
+		// other files, like sockets or devices, are skipped
+		return nil
+	}
+}
+
+// CreateZipArchive writes the files relative to current directory to zip archive
+func CreateZipArchive(w io.Writer, fileNames []string) error {
+	archive := zip.NewWriter(w)
+
+	for _, fileName := range fileNames {
+		fi, err := os.Lstat(fileName)
+		if err != nil {
+			return err
+		}
+
+		err = createZipFileEntry(archive, fileName, fi)
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
@@ -0,0 +1,60 @@
+package archives
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readZipEntry(t *testing.T, file *zip.File) string {
+	r, err := file.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCreateZipArchive(t *testing.T) {
+	var buffer bytes.Buffer
+	withTempDir(t, func() {
+		os.Mkdir("dir", 0755)
+		ioutil.WriteFile("dir/file", []byte("content"), 0640)
+		os.Symlink("file", "dir/link")
+
+		err := CreateZipArchive(&buffer, []string{"dir", "dir/file", "dir/link"})
+		assert.NoError(t, err)
+	})
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, archive.File, 3) {
+		assert.Equal(t, "dir/", archive.File[0].Name)
+		assert.True(t, archive.File[0].Mode().IsDir())
+
+		assert.Equal(t, "dir/file", archive.File[1].Name)
+		assert.Equal(t, os.FileMode(0640), archive.File[1].Mode())
+		assert.Equal(t, "content", readZipEntry(t, archive.File[1]))
+
+		assert.Equal(t, "dir/link", archive.File[2].Name)
+		assert.True(t, archive.File[2].Mode()&os.ModeSymlink != 0)
+		assert.Equal(t, "file", readZipEntry(t, archive.File[2]))
+	}
+}
+
+func TestCreateZipArchiveWithMissingFile(t *testing.T) {
+	var buffer bytes.Buffer
+	err := CreateZipArchive(&buffer, []string{"missing-file"})
+	assert.Error(t, err)
+}
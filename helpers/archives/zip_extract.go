@@ -0,0 +1,124 @@
+package archives
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func isZipEntryInWorkingDirectory(fileName string) bool {
+	fileName = filepath.Clean(fileName)
+	return !filepath.IsAbs(fileName) && fileName != ".." && !strings.HasPrefix(fileName, ".."+string(filepath.Separator))
+}
+
+// isSymlinkInPath checks whether any of parent directories is a symlink,
+// it would allow the archive to write outside of working directory
+func isSymlinkInPath(fileName string) bool {
+	for dir := filepath.Dir(fileName); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		fi, err := os.Lstat(dir)
+		if err == nil && fi.Mode()&os.ModeSymlink != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func extractZipDirectoryEntry(file *zip.File, fileName string) error {
+	err := os.MkdirAll(fileName, file.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	return os.Chmod(fileName, file.Mode().Perm())
+}
+
+func extractZipSymlinkEntry(file *zip.File, fileName string) error {
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	link, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	// the existing file is replaced
+	os.Remove(fileName)
+	return os.Symlink(string(link), fileName)
+}
+
+func extractZipFileEntry(file *zip.File, fileName string) error {
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// the existing file is replaced, it could be a read-only file or a symlink
+	os.Remove(fileName)
+
+	w, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, file.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	_, err = io.Copy(w, r)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func extractZipEntry(file *zip.File) error {
+	fileName := filepath.FromSlash(file.Name)
+	if !isZipEntryInWorkingDirectory(fileName) {
+		return fmt.Errorf("%s: the file is outside of working directory", file.Name)
+	}
+	if isSymlinkInPath(fileName) {
+		return fmt.Errorf("%s: the file is in symlinked directory", file.Name)
+	}
+
+	// the parent directories are not always stored in archive
+	err := os.MkdirAll(filepath.Dir(fileName), 0755)
+	if err != nil {
+		return err
+	}
+
+	switch mode := file.Mode(); {
+	case mode.IsDir():
+		err = extractZipDirectoryEntry(file, fileName)
+	case mode&os.ModeSymlink != 0:
+		return extractZipSymlinkEntry(file, fileName)
+	case mode.IsRegular():
+		err = extractZipFileEntry(file, fileName)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(fileName, file.ModTime(), file.ModTime())
+}
+
+// ExtractZipArchive extracts the zip archive to current directory
+func ExtractZipArchive(archiveFile string) error {
+	archive, err := zip.OpenReader(archiveFile)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		err = extractZipEntry(file)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package archives
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withTempDir(t *testing.T, fn func()) {
+	tempDir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(tempDir)
+
+	fn()
+}
+
+func writeTestArchive(t *testing.T, fileName string, entries ...*zip.FileHeader) {
+	file, err := os.Create(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	for _, entry := range entries {
+		w, err := archive.CreateHeader(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !entry.Mode().IsDir() {
+			w.Write([]byte("content"))
+		}
+	}
+	archive.Close()
+}
+
+func newTestZipEntry(name string, mode os.FileMode) *zip.FileHeader {
+	header := &zip.FileHeader{Name: name}
+	header.SetMode(mode)
+	return header
+}
+
+func TestExtractZipArchive(t *testing.T) {
+	withTempDir(t, func() {
+		writeTestArchive(t, "archive.zip",
+			newTestZipEntry("dir/", os.ModeDir|0755),
+			newTestZipEntry("dir/file", 0640),
+			newTestZipEntry("other/file", 0644))
+
+		err := ExtractZipArchive("archive.zip")
+		assert.NoError(t, err)
+
+		fi, err := os.Stat("dir/file")
+		if assert.NoError(t, err) {
+			assert.Equal(t, os.FileMode(0640), fi.Mode())
+		}
+
+		data, err := ioutil.ReadFile("other/file")
+		assert.NoError(t, err)
+		assert.Equal(t, "content", string(data))
+	})
+}
+
+func TestExtractZipArchiveOutsideOfWorkingDirectory(t *testing.T) {
+	withTempDir(t, func() {
+		writeTestArchive(t, "archive.zip", newTestZipEntry("../file", 0644))
+
+		err := ExtractZipArchive("archive.zip")
+		assert.Error(t, err)
+	})
+}
+
+func TestExtractZipArchiveThroughSymlink(t *testing.T) {
+	withTempDir(t, func() {
+		os.Symlink("..", "link")
+		writeTestArchive(t, "archive.zip", newTestZipEntry("link/file", 0644))
+
+		err := ExtractZipArchive("archive.zip")
+		assert.Error(t, err)
+	})
+}
@@ -1,8 +1,8 @@
 package cli_helpers
 
 import (
-	log "github.com/Sirupsen/logrus"
 	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
 	"os"
 )
 
@@ -1,11 +1,15 @@
 package docker_helpers
 
 import (
-	"strings"
-	"path"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"github.com/fsouza/go-dockerclient"
-	"os"
 	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
 )
 
 // IndexName is the name of the index
@@ -32,8 +36,90 @@ func SplitDockerImageName(reposName string) (string, string) {
 	return indexName, remoteName
 }
 
-func ReadDockerAuthConfigs(homeDir string) (*docker.AuthConfigurations, error) {
-	var r io.Reader
+// DockerAuthConfig is content of ~/.dockercfg, ~/.docker/config.json or DOCKER_AUTH_CONFIG
+type DockerAuthConfig struct {
+	Auths       *docker.AuthConfigurations
+	CredsStore  string
+	CredHelpers map[string]string
+}
+
+type dockerAuthEntry struct {
+	Auth  string `json:"auth"`
+	Email string `json:"email"`
+}
+
+type dockerConfigFile struct {
+	Auths       map[string]dockerAuthEntry `json:"auths"`
+	CredsStore  string                     `json:"credsStore"`
+	CredHelpers map[string]string          `json:"credHelpers"`
+}
+
+func isDockerConfigFile(keys map[string]json.RawMessage) bool {
+	for _, key := range []string{"auths", "credsStore", "credHelpers"} {
+		if _, ok := keys[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDockerAuthConfig reads the legacy .dockercfg or the newer config.json format
+func ParseDockerAuthConfig(r io.Reader) (*DockerAuthConfig, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys map[string]json.RawMessage
+	err = json.Unmarshal(data, &keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var configFile dockerConfigFile
+	if isDockerConfigFile(keys) {
+		err = json.Unmarshal(data, &configFile)
+	} else {
+		err = json.Unmarshal(data, &configFile.Auths)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	auths := &docker.AuthConfigurations{
+		Configs: make(map[string]docker.AuthConfiguration),
+	}
+	for registry, entry := range configFile.Auths {
+		// credentials are kept by credentials store
+		if entry.Auth == "" {
+			continue
+		}
+
+		data, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return nil, err
+		}
+		userpass := strings.SplitN(string(data), ":", 2)
+		if len(userpass) != 2 {
+			return nil, fmt.Errorf("invalid auth configuration for %s", registry)
+		}
+		auths.Configs[registry] = docker.AuthConfiguration{
+			Email:         entry.Email,
+			Username:      userpass[0],
+			Password:      userpass[1],
+			ServerAddress: registry,
+		}
+	}
+
+	return &DockerAuthConfig{
+		Auths:       auths,
+		CredsStore:  configFile.CredsStore,
+		CredHelpers: configFile.CredHelpers,
+	}, nil
+}
+
+func ReadDockerAuthConfigs(homeDir string) (*DockerAuthConfig, error) {
+	var r io.ReadCloser
 	var err error
 	p := path.Join(homeDir, ".docker", "config.json")
 	r, err = os.Open(p)
@@ -44,7 +130,23 @@ func ReadDockerAuthConfigs(homeDir string) (*docker.AuthConfigurations, error) {
 			return nil, err
 		}
 	}
-	return docker.NewAuthConfigurations(r)
+	defer r.Close()
+	return ParseDockerAuthConfig(r)
+}
+
+func convertToHostname(url string) string {
+	stripped := url
+	if strings.HasPrefix(url, "http://") {
+		stripped = strings.Replace(url, "http://", "", 1)
+	} else if strings.HasPrefix(url, "https://") {
+		stripped = strings.Replace(url, "https://", "", 1)
+	}
+
+	nameParts := strings.SplitN(stripped, "/", 2)
+	if nameParts[0] == "index."+DefaultDockerRegistry {
+		return DefaultDockerRegistry
+	}
+	return nameParts[0]
 }
 
 // Taken from: https://github.com/docker/docker/blob/master/registry/auth.go
@@ -53,21 +155,6 @@ func ResolveDockerAuthConfig(indexName string, configs *docker.AuthConfiguration
 		return nil
 	}
 
-	convertToHostname := func(url string) string {
-		stripped := url
-		if strings.HasPrefix(url, "http://") {
-			stripped = strings.Replace(url, "http://", "", 1)
-		} else if strings.HasPrefix(url, "https://") {
-			stripped = strings.Replace(url, "https://", "", 1)
-		}
-
-		nameParts := strings.SplitN(stripped, "/", 2)
-		if nameParts[0] == "index."+DefaultDockerRegistry {
-			return DefaultDockerRegistry
-		}
-		return nameParts[0]
-	}
-
 	// Maybe they have a legacy config file, we will iterate the keys converting
 	// them to the new format and testing
 	for registry, authConfig := range configs.Configs {
@@ -79,3 +166,60 @@ func ResolveDockerAuthConfig(indexName string, configs *docker.AuthConfiguration
 	// When all else fails, return an empty auth config
 	return nil
 }
+
+func (c *DockerAuthConfig) getCredentialHelper(indexName string) string {
+	for registry, helper := range c.CredHelpers {
+		if indexName == convertToHostname(registry) {
+			return helper
+		}
+	}
+	return c.CredsStore
+}
+
+// RestrictCredentialHelpers removes the credential helpers that are not present
+// on allowedHelpers and returns their names
+func (c *DockerAuthConfig) RestrictCredentialHelpers(allowedHelpers []string) (removed []string) {
+	isAllowed := func(helper string) bool {
+		for _, allowedHelper := range allowedHelpers {
+			if helper == allowedHelper {
+				return true
+			}
+		}
+		return false
+	}
+
+	if c.CredsStore != "" && !isAllowed(c.CredsStore) {
+		removed = append(removed, c.CredsStore)
+		c.CredsStore = ""
+	}
+
+	for registry, helper := range c.CredHelpers {
+		if !isAllowed(helper) {
+			removed = append(removed, helper)
+			delete(c.CredHelpers, registry)
+		}
+	}
+	return
+}
+
+// Resolve returns credentials for registry, the credential helpers
+// are asked first and the stored auths are used as fallback
+func (c *DockerAuthConfig) Resolve(indexName string) (*docker.AuthConfiguration, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	var err error
+	if helper := c.getCredentialHelper(indexName); helper != "" {
+		var authConfig *docker.AuthConfiguration
+		authConfig, err = GetCredentialsFromHelper(helper, indexName)
+		if authConfig != nil {
+			return authConfig, nil
+		}
+	}
+
+	if authConfig := ResolveDockerAuthConfig(indexName, c.Auths); authConfig != nil {
+		return authConfig, nil
+	}
+	return nil, err
+}
@@ -1,7 +1,15 @@
 package docker_helpers
 
 import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSplitDockerImageName(t *testing.T) {
@@ -48,3 +56,122 @@ func TestSplitDefaultIndexDockerImageName(t *testing.T) {
 		t.Error("Expected ", expectedImage, ", got ", image)
 	}
 }
+
+func encodeAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+func TestParseLegacyDockerAuthConfig(t *testing.T) {
+	config, err := ParseDockerAuthConfig(strings.NewReader(`{"https://index.docker.io/v1/": {"auth": "` + encodeAuth("user", "pass:word") + `", "email": "user@example.com"}}`))
+	assert.NoError(t, err)
+
+	authConfig, err := config.Resolve("docker.io")
+	assert.NoError(t, err)
+	if assert.NotNil(t, authConfig) {
+		assert.Equal(t, "user", authConfig.Username)
+		assert.Equal(t, "pass:word", authConfig.Password)
+		assert.Equal(t, "user@example.com", authConfig.Email)
+	}
+}
+
+func TestParseDockerAuthConfig(t *testing.T) {
+	config, err := ParseDockerAuthConfig(strings.NewReader(`{
+		"auths": {
+			"registry.example.com": {"auth": "` + encodeAuth("user", "password") + `"},
+			"gcr.io": {}
+		},
+		"credsStore": "osxkeychain",
+		"credHelpers": {"gcr.io": "gcloud"}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "osxkeychain", config.CredsStore)
+	assert.Equal(t, "gcloud", config.getCredentialHelper("gcr.io"))
+	assert.Equal(t, "osxkeychain", config.getCredentialHelper("registry.example.com"))
+	assert.Len(t, config.Auths.Configs, 1)
+
+	authConfig := ResolveDockerAuthConfig("registry.example.com", config.Auths)
+	if assert.NotNil(t, authConfig) {
+		assert.Equal(t, "user", authConfig.Username)
+		assert.Equal(t, "password", authConfig.Password)
+	}
+}
+
+func TestParseInvalidDockerAuthConfig(t *testing.T) {
+	_, err := ParseDockerAuthConfig(strings.NewReader(`{"auths": {"registry.example.com": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("user")) + `"}}}`))
+	assert.Error(t, err)
+
+	_, err = ParseDockerAuthConfig(strings.NewReader(`invalid`))
+	assert.Error(t, err)
+}
+
+func TestInvalidCredentialHelperName(t *testing.T) {
+	_, err := GetCredentialsFromHelper("../helper", "docker.io")
+	assert.Error(t, err)
+}
+
+func TestResolveWithCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper is a shell script")
+	}
+
+	dir, err := ioutil.TempDir("", "credential-helper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	script := `#!/bin/sh
+read server
+case "$server" in
+https://index.docker.io/v1/) echo '{"ServerURL": "'$server'", "Username": "helper-user", "Secret": "helper-secret"}' ;;
+*) echo "credentials not found in native keychain"; exit 1 ;;
+esac
+`
+	err = ioutil.WriteFile(filepath.Join(dir, "docker-credential-test"), []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldPath := os.Getenv("PATH")
+	defer os.Setenv("PATH", oldPath)
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+oldPath)
+
+	config, err := ParseDockerAuthConfig(strings.NewReader(`{
+		"auths": {"registry.example.com": {"auth": "` + encodeAuth("user", "password") + `"}},
+		"credsStore": "test"
+	}`))
+	assert.NoError(t, err)
+
+	authConfig, err := config.Resolve("docker.io")
+	assert.NoError(t, err)
+	if assert.NotNil(t, authConfig) {
+		assert.Equal(t, "helper-user", authConfig.Username)
+		assert.Equal(t, "helper-secret", authConfig.Password)
+		assert.Equal(t, "https://index.docker.io/v1/", authConfig.ServerAddress)
+	}
+
+	// falls back to stored credentials when helper doesn't have them
+	authConfig, err = config.Resolve("registry.example.com")
+	assert.NoError(t, err)
+	if assert.NotNil(t, authConfig) {
+		assert.Equal(t, "user", authConfig.Username)
+	}
+
+	authConfig, err = config.Resolve("other.example.com")
+	assert.NoError(t, err)
+	assert.Nil(t, authConfig)
+}
+
+func TestRestrictCredentialHelpers(t *testing.T) {
+	config, err := ParseDockerAuthConfig(strings.NewReader(`{
+		"credsStore": "osxkeychain",
+		"credHelpers": {"gcr.io": "gcloud", "registry.example.com": "secretservice"}
+	}`))
+	assert.NoError(t, err)
+
+	removed := config.RestrictCredentialHelpers([]string{"gcloud"})
+	assert.Len(t, removed, 2)
+	assert.Equal(t, "", config.CredsStore)
+	assert.Equal(t, "gcloud", config.getCredentialHelper("gcr.io"))
+	assert.Equal(t, "", config.getCredentialHelper("registry.example.com"))
+}
@@ -1,6 +1,7 @@
 package docker_helpers
 
 import (
+	"fmt"
 	"github.com/fsouza/go-dockerclient"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers"
 	"os"
@@ -8,10 +9,8 @@ import (
 	"strconv"
 )
 
-func Connect(c DockerCredentials, apiVersion string) (*docker.Client, error) {
-	endpoint := "unix:///var/run/docker.sock"
-	tlsVerify := false
-	tlsCertPath := ""
+func getEndpoint(c DockerCredentials) (endpoint string, tlsVerify bool, tlsCertPath string) {
+	endpoint = "unix:///var/run/docker.sock"
 
 	if host := helpers.StringOrDefault(c.Host, ""); host != "" {
 		// read docker config from config
@@ -26,6 +25,17 @@ func Connect(c DockerCredentials, apiVersion string) (*docker.Client, error) {
 		tlsVerify, _ = strconv.ParseBool(os.Getenv("DOCKER_TLS_VERIFY"))
 		tlsCertPath = os.Getenv("DOCKER_CERT_PATH")
 	}
+	return
+}
+
+// Endpoint returns address of Docker daemon used by the credentials
+func Endpoint(c DockerCredentials) string {
+	endpoint, _, _ := getEndpoint(c)
+	return endpoint
+}
+
+func Connect(c DockerCredentials, apiVersion string) (*docker.Client, error) {
+	endpoint, tlsVerify, tlsCertPath := getEndpoint(c)
 
 	if tlsVerify {
 		client, err := docker.NewVersionnedTLSClient(
@@ -49,3 +59,54 @@ func Connect(c DockerCredentials, apiVersion string) (*docker.Client, error) {
 		return client, nil
 	}
 }
+
+func negotiateAPIVersion(serverVersion, minVersion, maxVersion string) (docker.APIVersion, error) {
+	server, err := docker.NewAPIVersion(serverVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	min, err := docker.NewAPIVersion(minVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	max, err := docker.NewAPIVersion(maxVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if server.LessThan(min) {
+		return nil, fmt.Errorf("Docker API version %s is not supported, at least %s is required", server, min)
+	}
+
+	if server.GreaterThan(max) {
+		return max, nil
+	}
+	return server, nil
+}
+
+// ConnectNegotiated connects using the newest API version supported by both the daemon
+// and the runner, the daemons older than minVersion are rejected
+func ConnectNegotiated(c DockerCredentials, minVersion, maxVersion string) (*docker.Client, docker.APIVersion, error) {
+	client, err := Connect(c, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	env, err := client.Version()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	apiVersion, err := negotiateAPIVersion(env.Get("ApiVersion"), minVersion, maxVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err = Connect(c, apiVersion.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, apiVersion, nil
+}
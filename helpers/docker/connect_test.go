@@ -0,0 +1,28 @@
+package docker_helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateAPIVersion(t *testing.T) {
+	versions := map[string]string{
+		"1.22": "1.22",
+		"1.23": "1.23",
+		"1.24": "1.24",
+		"1.35": "1.24",
+	}
+
+	for server, expected := range versions {
+		version, err := negotiateAPIVersion(server, "1.22", "1.24")
+		assert.NoError(t, err, server)
+		assert.Equal(t, expected, version.String(), server)
+	}
+
+	_, err := negotiateAPIVersion("1.21", "1.22", "1.24")
+	assert.Error(t, err)
+
+	_, err = negotiateAPIVersion("", "1.22", "1.24")
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package docker_helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fsouza/go-dockerclient"
+)
+
+// Docker Hub credentials are stored by helpers under the legacy index address
+const defaultDockerRegistryURL = "https://index.docker.io/v1/"
+
+const credentialsNotFound = "credentials not found"
+
+// credentialHelperTimeout limits how long the image pull waits for credential helper
+const credentialHelperTimeout = 30 * time.Second
+
+var credentialHelperName = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+type credentialHelperResponse struct {
+	ServerURL string
+	Username  string
+	Secret    string
+}
+
+// GetCredentialsFromHelper asks the docker-credential-<helper> program
+// for credentials of registry, it returns nil when there are none
+func GetCredentialsFromHelper(helper, indexName string) (*docker.AuthConfiguration, error) {
+	// don't allow to execute programs outside of PATH
+	if !credentialHelperName.MatchString(helper) {
+		return nil, fmt.Errorf("invalid credential helper name: %q", helper)
+	}
+
+	serverURL := indexName
+	if indexName == DefaultDockerRegistry {
+		serverURL = defaultDockerRegistryURL
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	output, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("docker-credential-%s: timed out after %v", helper, credentialHelperTimeout)
+	} else if err != nil {
+		if strings.Contains(string(output), credentialsNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("docker-credential-%s: %v", helper, err)
+	}
+
+	var response credentialHelperResponse
+	err = json.Unmarshal(output, &response)
+	if err != nil {
+		return nil, fmt.Errorf("docker-credential-%s: %v", helper, err)
+	}
+
+	return &docker.AuthConfiguration{
+		Username:      response.Username,
+		Password:      response.Secret,
+		ServerAddress: serverURL,
+	}, nil
+}
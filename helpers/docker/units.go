@@ -0,0 +1,42 @@
+package docker_helpers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var sizeRegexp = regexp.MustCompile(`^(\d+(\.\d+)?)\s*([kKmMgGtT]?)[bB]?$`)
+
+var sizeUnits = map[string]float64{
+	"":  1,
+	"k": 1 << 10,
+	"m": 1 << 20,
+	"g": 1 << 30,
+	"t": 1 << 40,
+}
+
+// ParseSize converts human readable size (eg. 512m or 1.5g) to bytes,
+// the units are binary multiples the same as used by docker command line
+func ParseSize(size string) (int64, error) {
+	matches := sizeRegexp.FindStringSubmatch(strings.TrimSpace(size))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid size: %q", size)
+	}
+
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(value * sizeUnits[strings.ToLower(matches[3])]), nil
+}
+
+// ParseCPUs converts number of CPUs (eg. 1.5) to CPU quota for the given CPU period
+func ParseCPUs(cpus string, period int64) (int64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(cpus), 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid number of CPUs: %q", cpus)
+	}
+	return int64(value * float64(period)), nil
+}
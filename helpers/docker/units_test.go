@@ -0,0 +1,41 @@
+package docker_helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	sizes := map[string]int64{
+		"1024":  1024,
+		"10k":   10 * 1024,
+		"512m":  512 * 1024 * 1024,
+		"512MB": 512 * 1024 * 1024,
+		"1.5g":  1536 * 1024 * 1024,
+		"2G":    2 * 1024 * 1024 * 1024,
+	}
+
+	for size, expected := range sizes {
+		value, err := ParseSize(size)
+		assert.NoError(t, err, size)
+		assert.Equal(t, expected, value, size)
+	}
+
+	for _, size := range []string{"", "m", "-1m", "10x", "1.2.3g"} {
+		_, err := ParseSize(size)
+		assert.Error(t, err, size)
+	}
+}
+
+func TestParseCPUs(t *testing.T) {
+	quota, err := ParseCPUs("1.5", 100000)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150000), quota)
+
+	_, err = ParseCPUs("0", 100000)
+	assert.Error(t, err)
+
+	_, err = ParseCPUs("many", 100000)
+	assert.Error(t, err)
+}
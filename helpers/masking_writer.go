@@ -0,0 +1,122 @@
+package helpers
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+)
+
+const MaskedValue = "[MASKED]"
+
+// MaskingWriter replaces all occurrences of secret values with MaskedValue.
+// Data that could be the beginning of a secret is held back
+// until the next write or Flush, so secrets split between writes are masked too.
+type MaskingWriter struct {
+	writer  io.Writer
+	secrets [][]byte
+	pending []byte
+	lock    sync.Mutex
+}
+
+func (m *MaskingWriter) findSecret() (int, []byte) {
+	index := -1
+	var found []byte
+
+	// secrets are sorted from the longest, so on the same position the longest wins
+	for _, secret := range m.secrets {
+		if idx := bytes.Index(m.pending, secret); idx >= 0 && (index < 0 || idx < index) {
+			index = idx
+			found = secret
+		}
+	}
+	return index, found
+}
+
+// partialSecretLength returns the length of data at the end of pending,
+// that could be the beginning of a secret completed by the next write
+func (m *MaskingWriter) partialSecretLength() int {
+	partial := 0
+	for _, secret := range m.secrets {
+		// only the last len(secret)-1 bytes can start the secret
+		start := len(m.pending) - len(secret) + 1
+		if start < 0 {
+			start = 0
+		}
+
+		for ; start < len(m.pending)-partial; start++ {
+			if bytes.HasPrefix(secret, m.pending[start:]) {
+				partial = len(m.pending) - start
+				break
+			}
+		}
+	}
+	return partial
+}
+
+func (m *MaskingWriter) process(final bool) error {
+	for {
+		index, secret := m.findSecret()
+		if index < 0 {
+			break
+		}
+
+		if _, err := m.writer.Write(m.pending[0:index]); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(m.writer, MaskedValue); err != nil {
+			return err
+		}
+		m.pending = m.pending[index+len(secret):]
+	}
+
+	keep := 0
+	if !final {
+		keep = m.partialSecretLength()
+	}
+
+	if _, err := m.writer.Write(m.pending[0 : len(m.pending)-keep]); err != nil {
+		return err
+	}
+	m.pending = append([]byte{}, m.pending[len(m.pending)-keep:]...)
+	return nil
+}
+
+func (m *MaskingWriter) Write(data []byte) (int, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.pending = append(m.pending, data...)
+	if err := m.process(false); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Flush writes the data held back, because it looked like the beginning of a secret.
+func (m *MaskingWriter) Flush() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.process(true)
+}
+
+func NewMaskingWriter(writer io.Writer, secrets []string) *MaskingWriter {
+	m := &MaskingWriter{
+		writer: writer,
+	}
+
+	for _, secret := range secrets {
+		if secret != "" {
+			m.secrets = append(m.secrets, []byte(secret))
+		}
+	}
+	sort.Sort(bySecretLength(m.secrets))
+	return m
+}
+
+type bySecretLength [][]byte
+
+func (s bySecretLength) Len() int           { return len(s) }
+func (s bySecretLength) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s bySecretLength) Less(i, j int) bool { return len(s[i]) > len(s[j]) }
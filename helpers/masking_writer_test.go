@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskingWriter(t *testing.T) {
+	var tests = []struct {
+		secrets []string
+		writes  []string
+		out     string
+	}{
+		{[]string{"secret"}, []string{"my secret value"}, "my [MASKED] value"},
+		{[]string{"secret"}, []string{"my sec", "ret value"}, "my [MASKED] value"},
+		{[]string{"secret"}, []string{"s", "e", "c", "r", "e", "t"}, "[MASKED]"},
+		{[]string{"secret"}, []string{"my secre"}, "my secre"},
+		{[]string{"secret", "token"}, []string{"token secret secret"}, "[MASKED] [MASKED] [MASKED]"},
+		{[]string{"abc", "abcdef"}, []string{"abcdef abc"}, "[MASKED] [MASKED]"},
+		{[]string{""}, []string{"value"}, "value"},
+	}
+
+	for _, test := range tests {
+		var buffer bytes.Buffer
+		writer := NewMaskingWriter(&buffer, test.secrets)
+		for _, data := range test.writes {
+			n, err := writer.Write([]byte(data))
+			assert.NoError(t, err)
+			assert.Equal(t, len(data), n)
+		}
+		assert.NoError(t, writer.Flush())
+		assert.Equal(t, test.out, buffer.String())
+	}
+}
+
+func TestMaskingWriterHoldsBackPartialSecret(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewMaskingWriter(&buffer, []string{"secret"})
+
+	writer.Write([]byte("value: sec"))
+	assert.Equal(t, "value: ", buffer.String())
+
+	writer.Write([]byte("ond"))
+	assert.Equal(t, "value: second", buffer.String())
+}
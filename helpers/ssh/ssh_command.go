@@ -2,8 +2,10 @@ package ssh
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
+	"net"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -53,7 +55,34 @@ func (s *Command) getSSHAuthMethods() ([]ssh.AuthMethod, error) {
 	return methods, nil
 }
 
-func (s *Command) Connect() error {
+// dial connects to the SSH server, the connection
+// and the handshake are interrupted when ctx is done
+func (s *Command) dial(ctx context.Context, address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	handshakeFinished := make(chan bool)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-handshakeFinished:
+		}
+	}()
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+	close(handshakeFinished)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+func (s *Command) Connect(ctx context.Context) error {
 	host := helpers.StringOrDefault(s.Host, "localhost")
 	user := helpers.StringOrDefault(s.User, "root")
 	port := helpers.StringOrDefault(s.Port, "22")
@@ -76,13 +105,18 @@ func (s *Command) Connect() error {
 	var finalError error
 
 	for i := 0; i < connectRetries; i++ {
-		client, err := ssh.Dial("tcp", host+":"+port, config)
+		client, err := s.dial(ctx, net.JoinHostPort(host, port), config)
 		if err == nil {
 			s.client = client
 			return nil
 		}
-		time.Sleep(sshRetryInterval * time.Second)
 		finalError = err
+
+		select {
+		case <-time.After(sshRetryInterval * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	return finalError
@@ -104,7 +138,8 @@ func (s *Command) Exec(cmd string) error {
 	return err
 }
 
-func (s *Command) Run() error {
+// Run executes the command, it's killed when ctx is done
+func (s *Command) Run(ctx context.Context) error {
 	if s.client == nil {
 		return errors.New("Not connected")
 	}
@@ -125,9 +160,21 @@ func (s *Command) Run() error {
 	)
 	session.Stdout = s.Stdout
 	session.Stderr = s.Stderr
-	err = session.Run(s.Command)
-	session.Close()
-	return err
+	defer session.Close()
+
+	waitCh := make(chan error, 1)
+	go func() {
+		waitCh <- session.Run(s.Command)
+	}()
+
+	select {
+	case err = <-waitCh:
+		return err
+
+	case <-ctx.Done():
+		session.Signal(ssh.SIGKILL)
+		return ctx.Err()
+	}
 }
 
 func (s *Command) Cleanup() {
@@ -4,15 +4,18 @@ import (
 	"os"
 	"path"
 
-	log "github.com/Sirupsen/logrus"
+	log "github.com/sirupsen/logrus"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers/cli"
 	"github.com/codegangsta/cli"
 
 	"fmt"
+	_ "gitlab.com/gitlab-org/gitlab-ci-multi-runner/cache"
 	_ "gitlab.com/gitlab-org/gitlab-ci-multi-runner/commands"
 	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
 	_ "gitlab.com/gitlab-org/gitlab-ci-multi-runner/shells"
+	_ "gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors/custom"
 	_ "gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors/docker"
+	_ "gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors/kubernetes"
 	_ "gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors/parallels"
 	_ "gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors/shell"
 	_ "gitlab.com/gitlab-org/gitlab-ci-multi-runner/executors/ssh"
@@ -3,8 +3,58 @@ package shells
 import (
 	"fmt"
 	. "gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/helpers"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 )
 
+type gitStrategy string
+
+const (
+	gitClone gitStrategy = "clone"
+	gitFetch gitStrategy = "fetch"
+	gitNone  gitStrategy = "none"
+)
+
+type gitSubmoduleStrategy string
+
+const (
+	gitSubmoduleNone      gitSubmoduleStrategy = "none"
+	gitSubmoduleNormal    gitSubmoduleStrategy = "normal"
+	gitSubmoduleRecursive gitSubmoduleStrategy = "recursive"
+)
+
+type gitOptions struct {
+	Strategy    gitStrategy
+	Depth       int
+	Branch      string
+	Submodules  gitSubmoduleStrategy
+	LFS         bool
+	Credentials gitCredentials
+}
+
+const gitTokenVariable = "CI_BUILD_TOKEN"
+
+// the runner is expected to be in PATH of build environment if not configured otherwise
+const defaultRunnerCommand = "gitlab-ci-multi-runner"
+
+// the cache is shared by all builds of the same job and ref by default
+const defaultCacheKey = "$CI_BUILD_NAME/$CI_BUILD_REF_NAME"
+
+type cacheOptions struct {
+	Key         string
+	Paths       []string
+	Untracked   bool
+	DownloadURL string
+	UploadURL   string
+}
+
+const gitLFSMissingError = "The repository uses Git LFS, but git-lfs is not installed"
+
 type AbstractShell struct {
 }
 
@@ -14,7 +64,10 @@ func (s *AbstractShell) GetDefaultVariables(build *Build, projectDir string) []s
 		fmt.Sprintf("CI_BUILD_BEFORE_SHA=%s", build.BeforeSha),
 		fmt.Sprintf("CI_BUILD_REF_NAME=%s", build.RefName),
 		fmt.Sprintf("CI_BUILD_ID=%d", build.ID),
-		fmt.Sprintf("CI_BUILD_REPO=%s", build.RepoURL),
+		fmt.Sprintf("CI_BUILD_NAME=%s", build.Name),
+		fmt.Sprintf("CI_BUILD_STAGE=%s", build.Stage),
+		fmt.Sprintf("CI_BUILD_REPO=%s", s.getGitCredentials(build).RepoURL),
+		fmt.Sprintf("%s=%s", gitTokenVariable, s.getGitCredentials(build).Password),
 		fmt.Sprintf("CI_PROJECT_ID=%d", build.ProjectID),
 		fmt.Sprintf("CI_PROJECT_DIR=%s", projectDir),
 		"CI=true",
@@ -37,3 +90,266 @@ func (s *AbstractShell) GetBuildVariables(buildVariables []BuildVariable) []stri
 func (s *AbstractShell) GetVariables(build *Build, projectDir string, buildVariables []BuildVariable) []string {
 	return append(s.GetDefaultVariables(build, projectDir), s.GetBuildVariables(buildVariables)...)
 }
+
+func (s *AbstractShell) getRunnerCommand(info ShellScriptInfo) string {
+	if info.RunnerCommand != "" {
+		return info.RunnerCommand
+	}
+	return defaultRunnerCommand
+}
+
+// getArtifactsUploaderArgs returns the arguments of runner command uploading the build artifacts,
+// the build token is passed to it in environment
+func (s *AbstractShell) getArtifactsUploaderArgs(build *Build, artifacts *BuildArtifacts) []string {
+	args := []string{
+		"artifacts-uploader",
+		"--url", build.Runner.URL,
+		"--id", strconv.Itoa(build.ID),
+	}
+	for _, path := range artifacts.Paths {
+		args = append(args, "--path", path)
+	}
+	if artifacts.Untracked {
+		args = append(args, "--untracked")
+	}
+	if artifacts.Name != "" {
+		args = append(args, "--name", artifacts.Name)
+	}
+	if artifacts.ExpireIn != "" {
+		args = append(args, "--expire-in", artifacts.ExpireIn)
+	}
+	if build.Runner.MaxArtifactsSize != nil {
+		args = append(args, "--max-size", strconv.Itoa(*build.Runner.MaxArtifactsSize))
+	}
+	return args
+}
+
+// getArtifactsDependencies returns the dependencies which artifacts are downloaded before the build
+func (s *AbstractShell) getArtifactsDependencies(build *Build) (dependencies []BuildDependency) {
+	for _, dependency := range build.DependsOnBuilds {
+		if dependency.ArtifactsFile != nil && dependency.ArtifactsFile.Filename != "" {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return
+}
+
+// getArtifactsDownloaderArgs returns the arguments of runner command downloading the artifacts of dependency
+func (s *AbstractShell) getArtifactsDownloaderArgs(build *Build, dependency BuildDependency) []string {
+	args := []string{
+		"artifacts-downloader",
+		"--url", build.Runner.URL,
+		"--id", strconv.Itoa(dependency.ID),
+		"--token", dependency.Token,
+	}
+	if dependency.ArtifactsFile.Size > 0 {
+		args = append(args, "--size", strconv.FormatInt(dependency.ArtifactsFile.Size, 10))
+	}
+	if dependency.ArtifactsFile.SHA256 != "" {
+		args = append(args, "--sha256", dependency.ArtifactsFile.SHA256)
+	}
+	return args
+}
+
+// getCacheKey expands the build variables in cache key,
+// so the cache can be shared for example per ref or per job
+func (s *AbstractShell) getCacheKey(info ShellScriptInfo, key string) string {
+	if key == "" {
+		key = defaultCacheKey
+	}
+
+	variables := make(map[string]string)
+	for _, keyValue := range s.GetVariables(info.Build, info.Build.FullProjectDir(), info.Environment) {
+		parts := strings.SplitN(keyValue, "=", 2)
+		if len(parts) == 2 {
+			variables[parts[0]] = parts[1]
+		}
+	}
+	key = os.Expand(key, func(name string) string {
+		return variables[name]
+	})
+
+	// the key can't point outside of project cache
+	key = strings.Trim(path.Clean("/"+key), "/")
+	if key == "" {
+		key = "default"
+	}
+	return key
+}
+
+// getCacheOptions returns nil when the build doesn't use cache,
+// the URLs are empty when the runner doesn't have cache configured
+func (s *AbstractShell) getCacheOptions(info ShellScriptInfo) (*cacheOptions, error) {
+	build := info.Build
+	cache, ok := build.Options.GetCache()
+	if !ok {
+		return nil, nil
+	}
+
+	options := &cacheOptions{
+		Key:       s.getCacheKey(info, cache.Key),
+		Paths:     cache.Paths,
+		Untracked: cache.Untracked,
+	}
+
+	backend, err := NewCacheBackend(build.Runner.Cache)
+	if err != nil {
+		return nil, err
+	} else if backend == nil {
+		return options, nil
+	}
+
+	// the URLs have to be valid until the end of build
+	expires := time.Duration(build.Timeout)*time.Second + time.Hour
+	objectName := path.Join("project", strconv.Itoa(build.ProjectID), options.Key+".zip")
+
+	options.DownloadURL, err = backend.GetDownloadURL(objectName, expires)
+	if err != nil {
+		return nil, err
+	}
+	options.UploadURL, err = backend.GetUploadURL(objectName, expires)
+	if err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+func (s *AbstractShell) getCacheExtractorArgs(cache *cacheOptions) []string {
+	return []string{"cache-extractor", "--url", cache.DownloadURL}
+}
+
+func (s *AbstractShell) getCacheArchiverArgs(cache *cacheOptions) []string {
+	args := []string{"cache-archiver", "--url", cache.UploadURL}
+	for _, path := range cache.Paths {
+		args = append(args, "--path", path)
+	}
+	if cache.Untracked {
+		args = append(args, "--untracked")
+	}
+	return args
+}
+
+// getVariable returns value of build variable, the last definition wins
+func (s *AbstractShell) getVariable(buildVariables []BuildVariable, key string) (value string) {
+	for _, buildVariable := range buildVariables {
+		if buildVariable.Key == key {
+			value = buildVariable.Value
+		}
+	}
+	return
+}
+
+func (s *AbstractShell) getGitStrategy(info ShellScriptInfo) (gitStrategy, error) {
+	strategy := gitClone
+	if info.Build.AllowGitFetch {
+		strategy = gitFetch
+	}
+
+	strategy = gitStrategy(helpers.StringOrDefault(info.Build.Runner.GitStrategy, string(strategy)))
+	if value := s.getVariable(info.Environment, "GIT_STRATEGY"); value != "" {
+		strategy = gitStrategy(value)
+	}
+
+	switch strategy {
+	case gitClone, gitFetch, gitNone:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown GIT_STRATEGY: %q", strategy)
+	}
+}
+
+func (s *AbstractShell) getGitDepth(info ShellScriptInfo) (int, error) {
+	depth := helpers.NonZeroOrDefault(info.Build.Runner.GitDepth, 0)
+	if value := s.getVariable(info.Environment, "GIT_DEPTH"); value != "" {
+		var err error
+		depth, err = strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid GIT_DEPTH: %q", value)
+		}
+	}
+
+	if depth < 0 {
+		return 0, fmt.Errorf("invalid GIT_DEPTH: %d", depth)
+	}
+	return depth, nil
+}
+
+func (s *AbstractShell) getGitSubmoduleStrategy(info ShellScriptInfo) (gitSubmoduleStrategy, error) {
+	strategy := gitSubmoduleStrategy(s.getVariable(info.Environment, "GIT_SUBMODULE_STRATEGY"))
+
+	switch strategy {
+	case "":
+		return gitSubmoduleNone, nil
+	case gitSubmoduleNone, gitSubmoduleNormal, gitSubmoduleRecursive:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown GIT_SUBMODULE_STRATEGY: %q", strategy)
+	}
+}
+
+func (s *AbstractShell) getGitOptions(info ShellScriptInfo) (options gitOptions, err error) {
+	options.Strategy, err = s.getGitStrategy(info)
+	if err != nil {
+		return
+	}
+
+	options.Depth, err = s.getGitDepth(info)
+	if err != nil {
+		return
+	}
+
+	// detached HEAD uses commit SHA as ref name, that can't be cloned or fetched as branch
+	if info.Build.RefName != info.Build.Sha {
+		options.Branch = info.Build.RefName
+	}
+
+	options.Submodules, err = s.getGitSubmoduleStrategy(info)
+	if err != nil {
+		return
+	}
+
+	// the same variable is used by git-lfs to not download LFS objects on checkout
+	skipLFS, _ := strconv.ParseBool(s.getVariable(info.Environment, "GIT_LFS_SKIP_SMUDGE"))
+	options.LFS = !skipLFS
+
+	options.Credentials = s.getGitCredentials(info.Build)
+	return
+}
+
+// gitCredentials are passed to git by credential helper reading the password
+// from environment, so they are not stored in the repository config
+type gitCredentials struct {
+	RepoURL  string
+	HostURL  string
+	Username string
+	Password string
+}
+
+func (s *AbstractShell) getGitCredentials(build *Build) (credentials gitCredentials) {
+	credentials.RepoURL = build.RepoURL
+
+	repoURL, err := url.Parse(build.RepoURL)
+	if err != nil || repoURL.User == nil || repoURL.Host == "" {
+		return
+	}
+
+	credentials.Username = repoURL.User.Username()
+	credentials.Password, _ = repoURL.User.Password()
+
+	repoURL.User = nil
+	credentials.RepoURL = repoURL.String()
+	credentials.HostURL = (&url.URL{Scheme: repoURL.Scheme, Host: repoURL.Host}).String()
+	return
+}
+
+// HelperConfig returns the git config entry of credential helper, that is used
+// only for the repository host, so the submodules hosted there use the same credentials
+func (c gitCredentials) HelperConfig() (key string, value string, ok bool) {
+	if c.HostURL == "" {
+		return "", "", false
+	}
+
+	key = "credential." + c.HostURL + ".helper"
+	value = fmt.Sprintf("!f() { test $1 = get && echo username=%s && echo password=$%s; }; f", c.Username, gitTokenVariable)
+	return key, value, true
+}
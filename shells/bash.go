@@ -104,41 +104,90 @@ fi
 	return nil
 }
 
-func (b *BashShell) writeCloneCmd(w io.Writer, build *common.Build, projectDir string) {
+func (b *BashShell) writeCredentialHelperCmd(w io.Writer, credentials gitCredentials) {
+	if key, value, ok := credentials.HelperConfig(); ok {
+		io.WriteString(w, fmt.Sprintf("git config %s %s\n", helpers.ShellEscape(key), helpers.ShellEscape(value)))
+	}
+}
+
+func (b *BashShell) writeCloneCmd(w io.Writer, build *common.Build, projectDir string, git gitOptions) {
 	b.echoColoredFormat(w, "Cloning repository...")
 	io.WriteString(w, fmt.Sprintf("rm -rf %s\n", projectDir))
 	io.WriteString(w, fmt.Sprintf("mkdir -p %s\n", projectDir))
-	io.WriteString(w, fmt.Sprintf("git clone %s %s\n", helpers.ShellEscape(build.RepoURL), projectDir))
+
+	clone := "git"
+	if key, value, ok := git.Credentials.HelperConfig(); ok {
+		clone += " -c " + helpers.ShellEscape(key+"="+value)
+	}
+	clone += " clone"
+	if git.Depth > 0 {
+		clone += fmt.Sprintf(" --depth %d", git.Depth)
+		if git.Branch != "" {
+			clone += " --branch " + helpers.ShellEscape(git.Branch)
+		}
+	}
+	io.WriteString(w, fmt.Sprintf("%s %s %s\n", clone, helpers.ShellEscape(git.Credentials.RepoURL), projectDir))
 	io.WriteString(w, fmt.Sprintf("cd %s\n", projectDir))
+	b.writeCredentialHelperCmd(w, git.Credentials)
 }
 
-func (b *BashShell) writeFetchCmd(w io.Writer, build *common.Build, projectDir string, gitDir string) {
+func (b *BashShell) writeFetchCmd(w io.Writer, build *common.Build, projectDir string, gitDir string, git gitOptions) {
 	io.WriteString(w, fmt.Sprintf("if [[ -d %s ]]; then\n", gitDir))
 	b.echoColoredFormat(w, "Fetching changes...")
 	io.WriteString(w, fmt.Sprintf("cd %s\n", projectDir))
 	io.WriteString(w, fmt.Sprintf("git clean -ffdx\n"))
 	io.WriteString(w, fmt.Sprintf("git reset --hard > /dev/null\n"))
-	io.WriteString(w, fmt.Sprintf("git remote set-url origin %s\n", helpers.ShellEscape(build.RepoURL)))
-	io.WriteString(w, fmt.Sprintf("git fetch origin\n"))
+	io.WriteString(w, fmt.Sprintf("git remote set-url origin %s\n", helpers.ShellEscape(git.Credentials.RepoURL)))
+	b.writeCredentialHelperCmd(w, git.Credentials)
+	if git.Depth > 0 && git.Branch != "" {
+		io.WriteString(w, fmt.Sprintf("git fetch --depth %d origin %s\n", git.Depth, helpers.ShellEscape(git.Branch)))
+	} else if git.Depth > 0 {
+		io.WriteString(w, fmt.Sprintf("git fetch --depth %d origin\n", git.Depth))
+	} else {
+		io.WriteString(w, fmt.Sprintf("git fetch origin\n"))
+	}
 	io.WriteString(w, fmt.Sprintf("else\n"))
-	b.writeCloneCmd(w, build, projectDir)
+	b.writeCloneCmd(w, build, projectDir, git)
 	io.WriteString(w, fmt.Sprintf("fi\n"))
 }
 
-func (b *BashShell) writeCheckoutCmd(w io.Writer, build *common.Build) {
+func (b *BashShell) writeCheckoutCmd(w io.Writer, build *common.Build, depth int) {
+	if depth > 0 {
+		// the commit can be outside of shallow history of the ref
+		io.WriteString(w, fmt.Sprintf("if ! git cat-file -e %s 2>/dev/null; then\n", helpers.ShellEscape(build.Sha+"^{commit}")))
+		io.WriteString(w, fmt.Sprintf("git fetch --depth %d origin %s\n", depth, helpers.ShellEscape(build.Sha)))
+		io.WriteString(w, "fi\n")
+	}
 	b.echoColoredFormat(w, "Checking out %s as %s...", build.Sha[0:8], build.RefName)
 	io.WriteString(w, fmt.Sprintf("git checkout -qf %s\n", build.Sha))
 }
 
-func (b *BashShell) GenerateScript(info common.ShellScriptInfo) (*common.ShellScript, error) {
-	var buffer bytes.Buffer
-	w := bufio.NewWriter(&buffer)
+func (b *BashShell) writeSubmodulesCmd(w io.Writer, submodules gitSubmoduleStrategy) {
+	recursive := ""
+	if submodules == gitSubmoduleRecursive {
+		b.echoColoredFormat(w, "Updating/initializing submodules recursively...")
+		recursive = " --recursive"
+	} else {
+		b.echoColoredFormat(w, "Updating/initializing submodules...")
+	}
 
-	build := info.Build
-	projectDir := build.FullProjectDir()
-	projectDir = helpers.ToSlash(projectDir)
-	gitDir := filepath.Join(projectDir, ".git")
+	io.WriteString(w, fmt.Sprintf("git submodule sync%s\n", recursive))
+	io.WriteString(w, fmt.Sprintf("git submodule update --init%s\n", recursive))
+	io.WriteString(w, fmt.Sprintf("git submodule foreach%s 'git clean -ffdx'\n", recursive))
+	io.WriteString(w, fmt.Sprintf("git submodule foreach%s 'git reset --hard > /dev/null'\n", recursive))
+}
+
+func (b *BashShell) writeLFSCmd(w io.Writer) {
+	io.WriteString(w, "if git lfs version >/dev/null 2>/dev/null; then\n")
+	b.echoColoredFormat(w, "Fetching LFS objects...")
+	io.WriteString(w, "git lfs pull\n")
+	io.WriteString(w, "elif git grep -q filter=lfs -- '*.gitattributes'; then\n")
+	io.WriteString(w, "echo "+helpers.ShellEscape(helpers.ANSI_BOLD_RED+"ERROR: "+gitLFSMissingError+helpers.ANSI_RESET)+"\n")
+	io.WriteString(w, "exit 1\n")
+	io.WriteString(w, "fi\n")
+}
 
+func (b *BashShell) writeHostname(w io.Writer, build *common.Build) {
 	if len(build.Hostname) != 0 {
 		io.WriteString(w, fmt.Sprintf("echo Running on $(hostname) via %s...", helpers.ShellEscape(build.Hostname)))
 	} else {
@@ -147,29 +196,47 @@ func (b *BashShell) GenerateScript(info common.ShellScriptInfo) (*common.ShellSc
 	io.WriteString(w, "\n")
 	io.WriteString(w, "echo\n")
 	io.WriteString(w, "\n")
+}
 
+func (b *BashShell) writeExports(w io.Writer, info common.ShellScriptInfo, projectDir string) {
 	// Set env variables from build script
-	for _, keyValue := range b.GetVariables(build, projectDir, info.Environment) {
+	for _, keyValue := range b.GetVariables(info.Build, projectDir, info.Environment) {
 		io.WriteString(w, "export " + helpers.ShellEscape(keyValue) + "\n")
 	}
 	io.WriteString(w, "\n")
-	b.installGit(w)
-	io.WriteString(w, "\n")
-	io.WriteString(w, "set -eo pipefail\n")
-	io.WriteString(w, "\n")
+}
 
-	if build.AllowGitFetch {
-		b.writeFetchCmd(w, build, helpers.ShellEscape(projectDir), helpers.ShellEscape(gitDir))
-	} else {
-		b.writeCloneCmd(w, build, helpers.ShellEscape(projectDir))
+func (b *BashShell) writeSources(w io.Writer, build *common.Build, projectDir string, git gitOptions) {
+	gitDir := filepath.Join(projectDir, ".git")
+
+	switch git.Strategy {
+	case gitNone:
+		b.echoColoredFormat(w, "Skipping Git repository setup")
+		io.WriteString(w, fmt.Sprintf("mkdir -p %s\n", helpers.ShellEscape(projectDir)))
+		io.WriteString(w, fmt.Sprintf("cd %s\n", helpers.ShellEscape(projectDir)))
+		io.WriteString(w, "\n")
+		return
+
+	case gitFetch:
+		b.writeFetchCmd(w, build, helpers.ShellEscape(projectDir), helpers.ShellEscape(gitDir), git)
+
+	default:
+		b.writeCloneCmd(w, build, helpers.ShellEscape(projectDir), git)
 	}
 
-	b.writeCheckoutCmd(w, build)
+	b.writeCheckoutCmd(w, build, git.Depth)
+	if git.Submodules != gitSubmoduleNone {
+		b.writeSubmodulesCmd(w, git.Submodules)
+	}
+	if git.LFS {
+		b.writeLFSCmd(w)
+	}
 	io.WriteString(w, "\n")
 	io.WriteString(w, "echo\n")
 	io.WriteString(w, "\n")
+}
 
-	commands := build.Commands
+func (b *BashShell) writeCommands(w io.Writer, build *common.Build, commands string) {
 	commands = strings.TrimSpace(commands)
 	for _, command := range strings.Split(commands, "\n") {
 		command = strings.TrimSpace(command)
@@ -184,15 +251,204 @@ func (b *BashShell) GenerateScript(info common.ShellScriptInfo) (*common.ShellSc
 	}
 
 	io.WriteString(w, "\n")
+}
 
-	w.Flush()
+func (b *BashShell) writeRunnerCmd(w io.Writer, info common.ShellScriptInfo, description, missingWarning string, args []string) {
+	runnerCommand := b.getRunnerCommand(info)
 
+	var escapedArgs []string
+	for _, arg := range args {
+		escapedArgs = append(escapedArgs, helpers.ShellEscape(arg))
+	}
+
+	io.WriteString(w, fmt.Sprintf("if which %s >/dev/null 2>/dev/null; then\n", helpers.ShellEscape(runnerCommand)))
+	b.echoColored(w, description)
+	io.WriteString(w, fmt.Sprintf("%s %s\n", helpers.ShellEscape(runnerCommand), strings.Join(escapedArgs, " ")))
+	io.WriteString(w, "else\n")
+	io.WriteString(w, "echo "+helpers.ShellEscape(helpers.ANSI_BOLD_YELLOW+"WARNING: Missing "+runnerCommand+", "+missingWarning+helpers.ANSI_RESET)+"\n")
+	io.WriteString(w, "fi\n")
+}
+
+func (b *BashShell) writeDownloadArtifactsCmd(w io.Writer, info common.ShellScriptInfo, dependencies []common.BuildDependency) {
+	for _, dependency := range dependencies {
+		b.writeRunnerCmd(w, info,
+			fmt.Sprintf("Downloading artifacts for %s (%d)...", dependency.Name, dependency.ID),
+			"artifacts are not downloaded.",
+			b.getArtifactsDownloaderArgs(info.Build, dependency))
+	}
+	io.WriteString(w, "\n")
+}
+
+func (b *BashShell) writeRestoreCacheCmd(w io.Writer, info common.ShellScriptInfo, cache *cacheOptions) {
+	if cache.DownloadURL == "" {
+		io.WriteString(w, "echo "+helpers.ShellEscape(helpers.ANSI_BOLD_YELLOW+"WARNING: Cache is not configured for this runner, skipping."+helpers.ANSI_RESET)+"\n")
+	} else {
+		b.writeRunnerCmd(w, info,
+			fmt.Sprintf("Restoring cache %s...", cache.Key),
+			"cache is not restored.",
+			b.getCacheExtractorArgs(cache))
+	}
+	io.WriteString(w, "\n")
+}
+
+func (b *BashShell) writeArchiveCacheCmd(w io.Writer, info common.ShellScriptInfo, cache *cacheOptions) {
+	b.writeRunnerCmd(w, info,
+		fmt.Sprintf("Creating cache %s...", cache.Key),
+		"cache is not created.",
+		b.getCacheArchiverArgs(cache))
+	io.WriteString(w, "\n")
+}
+
+func (b *BashShell) writeUploadArtifactsCmd(w io.Writer, info common.ShellScriptInfo, artifacts *common.BuildArtifacts) {
+	b.writeRunnerCmd(w, info,
+		"Uploading artifacts...",
+		"artifacts are not uploaded.",
+		b.getArtifactsUploaderArgs(info.Build, artifacts))
+	io.WriteString(w, "\n")
+}
+
+func (b *BashShell) writeCdCmd(w io.Writer, projectDir string) {
+	io.WriteString(w, fmt.Sprintf("cd %s\n", helpers.ShellEscape(projectDir)))
+	io.WriteString(w, "\n")
+}
+
+func (b *BashShell) evalScript(script string) string {
 	// evaluate script in subcontext, this is required to close stdin
-	scriptCommand := "#!/usr/bin/env bash\n: | eval " + helpers.ShellEscape(buffer.String())
+	return ": | eval " + helpers.ShellEscape(script) + "\n"
+}
+
+func (b *BashShell) generateStage(info common.ShellScriptInfo, projectDir string, writeStage func(w io.Writer)) string {
+	var buffer bytes.Buffer
+	w := bufio.NewWriter(&buffer)
+
+	b.writeExports(w, info, projectDir)
+	io.WriteString(w, "set -eo pipefail\n")
+	io.WriteString(w, "\n")
+	writeStage(w)
+
+	w.Flush()
+	return buffer.String()
+}
+
+func (b *BashShell) generateStages(info common.ShellScriptInfo, projectDir string, git gitOptions, cache *cacheOptions) map[common.ShellScriptStage]string {
+	build := info.Build
+
+	stages := map[common.ShellScriptStage]string{
+		common.ShellPrepare: b.generateStage(info, projectDir, func(w io.Writer) {
+			b.installGit(w)
+		}),
+		common.ShellGetSources: b.generateStage(info, projectDir, func(w io.Writer) {
+			b.writeHostname(w, build)
+			b.writeSources(w, build, projectDir, git)
+		}),
+		common.ShellUserScript: b.generateStage(info, projectDir, func(w io.Writer) {
+			b.writeCdCmd(w, projectDir)
+			if commands, ok := build.Options.GetCommands("before_script"); ok {
+				b.writeCommands(w, build, commands)
+			}
+			b.writeCommands(w, build, build.Commands)
+		}),
+	}
+
+	if cache != nil {
+		stages[common.ShellRestoreCache] = b.generateStage(info, projectDir, func(w io.Writer) {
+			b.writeCdCmd(w, projectDir)
+			b.writeRestoreCacheCmd(w, info, cache)
+		})
+	}
+
+	if cache != nil && cache.UploadURL != "" {
+		stages[common.ShellArchiveCache] = b.generateStage(info, projectDir, func(w io.Writer) {
+			b.writeCdCmd(w, projectDir)
+			b.writeArchiveCacheCmd(w, info, cache)
+		})
+	}
+
+	if dependencies := b.getArtifactsDependencies(build); len(dependencies) != 0 {
+		stages[common.ShellDownloadArtifacts] = b.generateStage(info, projectDir, func(w io.Writer) {
+			b.writeCdCmd(w, projectDir)
+			b.writeDownloadArtifactsCmd(w, info, dependencies)
+		})
+	}
+
+	if commands, ok := build.Options.GetCommands("after_script"); ok {
+		stages[common.ShellAfterScript] = b.generateStage(info, projectDir, func(w io.Writer) {
+			b.writeCdCmd(w, projectDir)
+			b.writeCommands(w, build, commands)
+		})
+	}
+
+	if artifacts, ok := build.Options.GetArtifacts(); ok {
+		stages[common.ShellUploadArtifacts] = b.generateStage(info, projectDir, func(w io.Writer) {
+			b.writeCdCmd(w, projectDir)
+			b.writeUploadArtifactsCmd(w, info, artifacts)
+		})
+	}
+	return stages
+}
+
+func (b *BashShell) GenerateScript(info common.ShellScriptInfo) (*common.ShellScript, error) {
+	var buffer bytes.Buffer
+	w := bufio.NewWriter(&buffer)
+
+	build := info.Build
+	projectDir := build.FullProjectDir()
+	projectDir = helpers.ToSlash(projectDir)
+
+	git, err := b.getGitOptions(info)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := b.getCacheOptions(info)
+	if err != nil {
+		return nil, err
+	}
+
+	b.writeHostname(w, build)
+	b.writeExports(w, info, projectDir)
+	b.installGit(w)
+	io.WriteString(w, "\n")
+	io.WriteString(w, "set -eo pipefail\n")
+	io.WriteString(w, "\n")
+	b.writeSources(w, build, projectDir, git)
+	if cache != nil {
+		b.writeRestoreCacheCmd(w, info, cache)
+	}
+	if dependencies := b.getArtifactsDependencies(build); len(dependencies) != 0 {
+		b.writeDownloadArtifactsCmd(w, info, dependencies)
+	}
+	if commands, ok := build.Options.GetCommands("before_script"); ok {
+		b.writeCommands(w, build, commands)
+	}
+	b.writeCommands(w, build, build.Commands)
+	if cache != nil && cache.UploadURL != "" {
+		b.writeArchiveCacheCmd(w, info, cache)
+	}
+	if artifacts, ok := build.Options.GetArtifacts(); ok {
+		b.writeUploadArtifactsCmd(w, info, artifacts)
+	}
+
+	w.Flush()
+
+	stages := b.generateStages(info, projectDir, git, cache)
+	scriptCommand := "#!/usr/bin/env bash\n" + b.evalScript(buffer.String())
+
+	// after_script is executed even if the build failed
+	if afterScript, ok := stages[common.ShellAfterScript]; ok {
+		scriptCommand += "build_status=$?\n"
+		scriptCommand += b.evalScript(afterScript)
+		scriptCommand += "exit $build_status\n"
+	}
 
 	script := common.ShellScript{
 		Script:      scriptCommand,
 		Environment: b.GetVariables(build, projectDir, info.Environment),
+		Stages:      make(map[common.ShellScriptStage]string),
+	}
+
+	for stage, stageScript := range stages {
+		script.Stages[stage] = "#!/usr/bin/env bash\n" + b.evalScript(stageScript)
 	}
 
 	// su
@@ -0,0 +1,57 @@
+package shells
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/gitlab-org/gitlab-ci-multi-runner/common"
+)
+
+func TestBashBeforeScriptIsPartOfUserScript(t *testing.T) {
+	info := newGitInfo(common.RunnerConfig{})
+	info.Build.Sha = "1234567890abcdef"
+	info.Build.Commands = "echo build-command"
+	info.Build.Options = common.BuildOptions{
+		"before_script": []interface{}{"echo before-command"},
+		"after_script":  []interface{}{"echo after-command"},
+	}
+
+	script, err := (&BashShell{}).GenerateScript(info)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	userScript, ok := script.GetStageScript(common.ShellUserScript)
+	if assert.True(t, ok) {
+		assert.Contains(t, userScript, "before-command")
+		assert.Contains(t, userScript, "build-command")
+		assert.NotContains(t, userScript, "after-command")
+	}
+
+	afterScript, ok := script.GetStageScript(common.ShellAfterScript)
+	if assert.True(t, ok) {
+		assert.Contains(t, afterScript, "after-command")
+	}
+	assert.Len(t, script.Stages, 4)
+}
+
+func TestBashShallowCloneOfDetachedHead(t *testing.T) {
+	depth := 1
+	info := newGitInfo(common.RunnerConfig{GitDepth: &depth})
+	info.Build.Sha = "1234567890abcdef"
+	info.Build.RepoURL = "https://gitlab.example.com/group/project.git"
+
+	info.Build.RefName = "feature"
+	script, err := (&BashShell{}).GenerateScript(info)
+	if assert.NoError(t, err) {
+		assert.Contains(t, script.Stages[common.ShellGetSources], "git clone --depth 1 --branch")
+	}
+
+	// the exec command uses SHA as ref name of detached HEAD, the commit is fetched before checkout
+	info.Build.RefName = info.Build.Sha
+	script, err = (&BashShell{}).GenerateScript(info)
+	if assert.NoError(t, err) {
+		assert.NotContains(t, script.Stages[common.ShellGetSources], "--branch")
+	}
+}
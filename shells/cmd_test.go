@@ -0,0 +1,14 @@
+package shells
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdQuoteArg(t *testing.T) {
+	shell := &CmdShell{}
+
+	assert.Equal(t, `"feature/branch"`, shell.quoteArg("feature/branch"))
+	assert.Equal(t, `"a""b&c%%d"`, shell.quoteArg(`a"b&c%d`))
+}
@@ -22,12 +22,23 @@ func (b *PowerShell) writeCommand(w io.Writer, format string, args ...interface{
 	io.WriteString(w, fmt.Sprintf(format, args...)+"\r\n")
 }
 
+// quoteArg quotes the argument, so it's passed to the command as it is
+func (b *PowerShell) quoteArg(arg string) string {
+	return "'" + strings.Replace(arg, "'", "''", -1) + "'"
+}
+
 func (b *PowerShell) writeCommandChecked(w io.Writer, format string, args ...interface{}) {
 	b.writeCommand(w, format, args...)
 	b.writeCommand(w, "%s", "if (!$?) { Exit $LASTEXITCODE }")
 }
 
-func (b *PowerShell) writeCloneCmd(w io.Writer, build *common.Build, dir string) {
+func (b *PowerShell) writeCredentialHelperCmd(w io.Writer, credentials gitCredentials) {
+	if key, value, ok := credentials.HelperConfig(); ok {
+		b.writeCommandChecked(w, "git config '%s' '%s'", key, value)
+	}
+}
+
+func (b *PowerShell) writeCloneCmd(w io.Writer, build *common.Build, dir string, git gitOptions) {
 	b.writeCommand(w, "echo \"Cloning repository...\"")
 	b.writeCommand(w, "Import-Module -Name NTFSSecurity -ErrorAction SilentlyContinue")
 	b.writeCommand(w, "if( (Get-Command -Name Remove-Item2 -Module NTFSSecurity -ErrorAction SilentlyContinue) -and (Test-Path \"%s\") ) {", dir)
@@ -36,38 +47,123 @@ func (b *PowerShell) writeCloneCmd(w io.Writer, build *common.Build, dir string)
 	b.writeCommandChecked(w, "Remove-Item -Force -Recurse \"%s\"", dir)
 	b.writeCommand(w, "}")
 	b.writeCommandChecked(w, "(Test-Path \"%s\") -or (New-Item \"%s\" -ItemType \"directory\" )", dir, dir)
-	b.writeCommandChecked(w, "git clone \"%s\" \"%s\"", build.RepoURL, dir)
+	clone := "git"
+	if key, value, ok := git.Credentials.HelperConfig(); ok {
+		clone += fmt.Sprintf(" -c '%s=%s'", key, value)
+	}
+	clone += " clone"
+	if git.Depth > 0 {
+		clone += fmt.Sprintf(" --depth %d", git.Depth)
+		if git.Branch != "" {
+			clone += " --branch " + b.quoteArg(git.Branch)
+		}
+	}
+	b.writeCommandChecked(w, "%s \"%s\" \"%s\"", clone, git.Credentials.RepoURL, dir)
 	b.writeCommandChecked(w, "cd \"%s\"", dir)
+	b.writeCredentialHelperCmd(w, git.Credentials)
 }
 
-func (b *PowerShell) writeFetchCmd(w io.Writer, build *common.Build, dir string) {
+func (b *PowerShell) writeFetchCmd(w io.Writer, build *common.Build, dir string, git gitOptions) {
 	b.writeCommand(w, "if(Test-Path \"%s\\.git\") {", dir)
 	b.writeCommand(w, "echo \"Fetching changes...\"")
 	b.writeCommandChecked(w, "cd \"%s\"", dir)
 	b.writeCommandChecked(w, "git clean -ffdx")
 	b.writeCommandChecked(w, "git reset --hard > $null")
-	b.writeCommandChecked(w, "git remote set-url origin \"%s\"", build.RepoURL)
-	b.writeCommandChecked(w, "git fetch origin")
+	b.writeCommandChecked(w, "git remote set-url origin \"%s\"", git.Credentials.RepoURL)
+	b.writeCredentialHelperCmd(w, git.Credentials)
+	if git.Depth > 0 && git.Branch != "" {
+		b.writeCommandChecked(w, "git fetch --depth %d origin %s", git.Depth, b.quoteArg(git.Branch))
+	} else if git.Depth > 0 {
+		b.writeCommandChecked(w, "git fetch --depth %d origin", git.Depth)
+	} else {
+		b.writeCommandChecked(w, "git fetch origin")
+	}
 	b.writeCommand(w, "} else {")
-	b.writeCloneCmd(w, build, dir)
+	b.writeCloneCmd(w, build, dir, git)
 	b.writeCommand(w, "}")
 }
 
-func (b *PowerShell) writeCheckoutCmd(w io.Writer, build *common.Build) {
+func (b *PowerShell) writeCheckoutCmd(w io.Writer, build *common.Build, depth int) {
+	if depth > 0 {
+		// the commit can be outside of shallow history of the ref
+		b.writeCommand(w, "$ErrorActionPreference = \"Continue\"")
+		b.writeCommand(w, "git cat-file -e \"%s^{commit}\" 2> $null", build.Sha)
+		b.writeCommand(w, "$ErrorActionPreference = \"Stop\"")
+		b.writeCommand(w, "if ($LASTEXITCODE -ne 0) {")
+		b.writeCommandChecked(w, "git fetch --depth %d origin \"%s\"", depth, build.Sha)
+		b.writeCommand(w, "}")
+	}
 	b.writeCommand(w, "echo \"Checking out %s as %s...\"", build.Sha[0:8], build.RefName)
 	b.writeCommandChecked(w, "git checkout -qf \"%s\"", build.Sha)
 }
 
-func (b *PowerShell) GenerateScript(info common.ShellScriptInfo) (*common.ShellScript, error) {
-	var buffer bytes.Buffer
-	w := bufio.NewWriter(&buffer)
+func (b *PowerShell) writeSubmodulesCmd(w io.Writer, submodules gitSubmoduleStrategy) {
+	recursive := ""
+	if submodules == gitSubmoduleRecursive {
+		b.writeCommand(w, "echo \"Updating/initializing submodules recursively...\"")
+		recursive = " --recursive"
+	} else {
+		b.writeCommand(w, "echo \"Updating/initializing submodules...\"")
+	}
 
-	build := info.Build
-	projectDir := build.FullProjectDir()
-	projectDir = helpers.ToBackslash(projectDir)
+	b.writeCommandChecked(w, "git submodule sync%s", recursive)
+	b.writeCommandChecked(w, "git submodule update --init%s", recursive)
+	b.writeCommandChecked(w, "git submodule foreach%s \"git clean -ffdx\"", recursive)
+	b.writeCommandChecked(w, "git submodule foreach%s \"git reset --hard\"", recursive)
+}
 
+func (b *PowerShell) writeLFSCmd(w io.Writer) {
+	b.writeCommand(w, "$ErrorActionPreference = \"Continue\"")
+	b.writeCommand(w, "git lfs version 2> $null | Out-Null")
+	b.writeCommand(w, "$lfsInstalled = ($LASTEXITCODE -eq 0)")
 	b.writeCommand(w, "$ErrorActionPreference = \"Stop\"")
+	b.writeCommand(w, "if ($lfsInstalled) {")
+	b.writeCommand(w, "echo \"Fetching LFS objects...\"")
+	b.writeCommandChecked(w, "git lfs pull")
+	b.writeCommand(w, "} else {")
+	b.writeCommand(w, "git grep -q filter=lfs -- \"*.gitattributes\"")
+	b.writeCommand(w, "if ($LASTEXITCODE -eq 0) {")
+	b.writeCommand(w, "echo \"ERROR: %s\"", gitLFSMissingError)
+	b.writeCommand(w, "Exit 1")
+	b.writeCommand(w, "}")
+	b.writeCommand(w, "}")
+}
+
+func (b *PowerShell) writeRunnerCmd(w io.Writer, info common.ShellScriptInfo, args []string) {
+	var quotedArgs []string
+	for _, arg := range args {
+		quotedArgs = append(quotedArgs, b.quoteArg(arg))
+	}
+	b.writeCommandChecked(w, "& '%s' %s", b.getRunnerCommand(info), strings.Join(quotedArgs, " "))
+}
+
+func (b *PowerShell) writeDownloadArtifactsCmd(w io.Writer, info common.ShellScriptInfo, dependencies []common.BuildDependency) {
+	for _, dependency := range dependencies {
+		b.writeCommand(w, "echo \"Downloading artifacts for %s (%d)...\"", dependency.Name, dependency.ID)
+		b.writeRunnerCmd(w, info, b.getArtifactsDownloaderArgs(info.Build, dependency))
+	}
+}
+
+func (b *PowerShell) writeRestoreCacheCmd(w io.Writer, info common.ShellScriptInfo, cache *cacheOptions) {
+	if cache.DownloadURL == "" {
+		b.writeCommand(w, "echo \"WARNING: Cache is not configured for this runner, skipping.\"")
+		return
+	}
+	b.writeCommand(w, "echo \"%s\"", "Restoring cache "+cache.Key+"...")
+	b.writeRunnerCmd(w, info, b.getCacheExtractorArgs(cache))
+}
+
+func (b *PowerShell) writeArchiveCacheCmd(w io.Writer, info common.ShellScriptInfo, cache *cacheOptions) {
+	b.writeCommand(w, "echo \"%s\"", "Creating cache "+cache.Key+"...")
+	b.writeRunnerCmd(w, info, b.getCacheArchiverArgs(cache))
+}
+
+func (b *PowerShell) writeUploadArtifactsCmd(w io.Writer, info common.ShellScriptInfo, artifacts *common.BuildArtifacts) {
+	b.writeCommand(w, "echo \"Uploading artifacts...\"")
+	b.writeRunnerCmd(w, info, b.getArtifactsUploaderArgs(info.Build, artifacts))
+}
 
+func (b *PowerShell) writeSources(w io.Writer, build *common.Build, projectDir string, git gitOptions) {
 	if len(build.Hostname) != 0 {
 		b.writeCommand(w, "echo \"Running on $env:computername via %s...\"", helpers.ShellEscape(build.Hostname))
 	} else {
@@ -75,16 +171,33 @@ func (b *PowerShell) GenerateScript(info common.ShellScriptInfo) (*common.ShellS
 	}
 	b.writeCommand(w, "")
 
-	if build.AllowGitFetch {
-		b.writeFetchCmd(w, build, projectDir)
-	} else {
-		b.writeCloneCmd(w, build, projectDir)
+	switch git.Strategy {
+	case gitNone:
+		b.writeCommand(w, "echo \"Skipping Git repository setup\"")
+		b.writeCommandChecked(w, "(Test-Path \"%s\") -or (New-Item \"%s\" -ItemType \"directory\" )", projectDir, projectDir)
+		b.writeCommandChecked(w, "cd \"%s\"", projectDir)
+		b.writeCommand(w, "")
+		return
+
+	case gitFetch:
+		b.writeFetchCmd(w, build, projectDir, git)
+
+	default:
+		b.writeCloneCmd(w, build, projectDir, git)
 	}
 
-	b.writeCheckoutCmd(w, build)
+	b.writeCheckoutCmd(w, build, git.Depth)
+	if git.Submodules != gitSubmoduleNone {
+		b.writeSubmodulesCmd(w, git.Submodules)
+	}
+	if git.LFS {
+		b.writeLFSCmd(w)
+	}
 	b.writeCommand(w, "")
+}
 
-	for _, command := range strings.Split(build.Commands, "\n") {
+func (b *PowerShell) writeCommands(w io.Writer, build *common.Build, commands string, checked bool) {
+	for _, command := range strings.Split(commands, "\n") {
 		command = strings.TrimRight(command, " \t\r\n")
 		if strings.TrimSpace(command) == "" {
 			b.writeCommand(w, "echo \"\"")
@@ -94,11 +207,132 @@ func (b *PowerShell) GenerateScript(info common.ShellScriptInfo) (*common.ShellS
 		if !helpers.BoolOrDefault(build.Runner.DisableVerbose, false) {
 			b.writeCommand(w, "echo \"%s\"", command)
 		}
-		b.writeCommandChecked(w, "%s", command)
+		if checked {
+			b.writeCommandChecked(w, "%s", command)
+		} else {
+			b.writeCommand(w, "%s", command)
+		}
+	}
+}
+
+func (b *PowerShell) generateStage(writeStage func(w io.Writer)) string {
+	var buffer bytes.Buffer
+	w := bufio.NewWriter(&buffer)
+
+	b.writeCommand(w, "$ErrorActionPreference = \"Stop\"")
+	writeStage(w)
+
+	w.Flush()
+	return buffer.String()
+}
+
+func (b *PowerShell) generateCommandsStage(build *common.Build, projectDir string, commands ...string) string {
+	return b.generateStage(func(w io.Writer) {
+		b.writeCommandChecked(w, "cd \"%s\"", projectDir)
+		for _, command := range commands {
+			b.writeCommands(w, build, command, true)
+		}
+	})
+}
+
+func (b *PowerShell) GenerateScript(info common.ShellScriptInfo) (*common.ShellScript, error) {
+	var buffer bytes.Buffer
+	w := bufio.NewWriter(&buffer)
+
+	build := info.Build
+	projectDir := build.FullProjectDir()
+	projectDir = helpers.ToBackslash(projectDir)
+
+	git, err := b.getGitOptions(info)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := b.getCacheOptions(info)
+	if err != nil {
+		return nil, err
+	}
+
+	beforeScript, hasBeforeScript := build.Options.GetCommands("before_script")
+	afterScript, hasAfterScript := build.Options.GetCommands("after_script")
+	artifacts, hasArtifacts := build.Options.GetArtifacts()
+	dependencies := b.getArtifactsDependencies(build)
+
+	b.writeCommand(w, "$ErrorActionPreference = \"Stop\"")
+
+	// after_script is executed even if the build failed
+	if hasAfterScript {
+		b.writeCommand(w, "try {")
+	}
+
+	b.writeSources(w, build, projectDir, git)
+	if cache != nil {
+		b.writeRestoreCacheCmd(w, info, cache)
+	}
+	if len(dependencies) != 0 {
+		b.writeDownloadArtifactsCmd(w, info, dependencies)
+	}
+	if hasBeforeScript {
+		b.writeCommands(w, build, beforeScript, true)
+	}
+	b.writeCommands(w, build, build.Commands, true)
+	if cache != nil && cache.UploadURL != "" {
+		b.writeArchiveCacheCmd(w, info, cache)
+	}
+	if hasArtifacts {
+		b.writeUploadArtifactsCmd(w, info, artifacts)
+	}
+
+	if hasAfterScript {
+		b.writeCommand(w, "} finally {")
+		b.writeCommand(w, "$ErrorActionPreference = \"Continue\"")
+		b.writeCommand(w, "cd \"%s\"", projectDir)
+		b.writeCommands(w, build, afterScript, false)
+		b.writeCommand(w, "}")
 	}
 
 	w.Flush()
 
+	// before_script is executed in the same stage as the build commands
+	userScript := []string{build.Commands}
+	if hasBeforeScript {
+		userScript = []string{beforeScript, build.Commands}
+	}
+
+	stages := map[common.ShellScriptStage]string{
+		common.ShellGetSources: b.generateStage(func(w io.Writer) {
+			b.writeSources(w, build, projectDir, git)
+		}),
+		common.ShellUserScript: b.generateCommandsStage(build, projectDir, userScript...),
+	}
+	if cache != nil {
+		stages[common.ShellRestoreCache] = b.generateStage(func(w io.Writer) {
+			b.writeCommandChecked(w, "cd \"%s\"", projectDir)
+			b.writeRestoreCacheCmd(w, info, cache)
+		})
+	}
+	if cache != nil && cache.UploadURL != "" {
+		stages[common.ShellArchiveCache] = b.generateStage(func(w io.Writer) {
+			b.writeCommandChecked(w, "cd \"%s\"", projectDir)
+			b.writeArchiveCacheCmd(w, info, cache)
+		})
+	}
+	if len(dependencies) != 0 {
+		stages[common.ShellDownloadArtifacts] = b.generateStage(func(w io.Writer) {
+			b.writeCommandChecked(w, "cd \"%s\"", projectDir)
+			b.writeDownloadArtifactsCmd(w, info, dependencies)
+		})
+	}
+	if hasAfterScript {
+		stages[common.ShellAfterScript] = b.generateCommandsStage(build, projectDir, afterScript)
+	}
+	if hasArtifacts {
+		stages[common.ShellUploadArtifacts] = b.generateStage(func(w io.Writer) {
+			b.writeCommandChecked(w, "cd \"%s\"", projectDir)
+			b.writeUploadArtifactsCmd(w, info, artifacts)
+		})
+	}
+
 	script := common.ShellScript{
 		Environment: b.GetVariables(build, projectDir, info.Environment),
 		Script:      buffer.String(),
@@ -106,6 +340,7 @@ func (b *PowerShell) GenerateScript(info common.ShellScriptInfo) (*common.ShellS
 		Arguments:   []string{"-noprofile", "-noninteractive", "-executionpolicy", "Bypass", "-command"},
 		PassFile:    true,
 		Extension:   "ps1",
+		Stages:      stages,
 	}
 	return &script, nil
 }
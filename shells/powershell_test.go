@@ -0,0 +1,14 @@
+package shells
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerShellQuoteArg(t *testing.T) {
+	shell := &PowerShell{}
+
+	assert.Equal(t, `'feature/branch'`, shell.quoteArg("feature/branch"))
+	assert.Equal(t, `'a''b"c&d$e'`, shell.quoteArg(`a'b"c&d$e`))
+}